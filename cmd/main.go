@@ -7,8 +7,14 @@ import (
 	"flag"
 	"log"
 	"os"
+	"strconv"
+	"time"
 )
 
+// defaultTrashRetentionDays is how long deleted words stay in the trash
+// unless TRASH_RETENTION_DAYS is set.
+const defaultTrashRetentionDays = 30
+
 var (
 	showVersion bool
 )
@@ -36,8 +42,18 @@ func main() {
 		log.Fatal("BOT_TOKEN environment variable is not set")
 	}
 
+	// Get trash retention period from environment variable
+	retentionDays := defaultTrashRetentionDays
+	if value := os.Getenv("TRASH_RETENTION_DAYS"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 {
+			log.Fatal("TRASH_RETENTION_DAYS must be a positive number of days")
+		}
+		retentionDays = days
+	}
+
 	// Create and start bot
-	b, err := bot.NewBot(token)
+	b, err := bot.NewBot(token, time.Duration(retentionDays)*24*time.Hour)
 	if err != nil {
 		log.Fatal("Error creating bot:", err)
 	}
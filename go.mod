@@ -3,6 +3,7 @@ module english-words-bot
 go 1.22
 
 require (
+	golang.org/x/text v0.14.0
 	gopkg.in/telebot.v3 v3.3.8
 	gorm.io/driver/sqlite v1.5.5
 	gorm.io/gorm v1.25.7
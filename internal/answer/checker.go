@@ -0,0 +1,132 @@
+package answer
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Verdict is the result of checking an answer.
+type Verdict int
+
+const (
+	// Wrong means the answer does not match any accepted translation.
+	Wrong Verdict = iota
+	// NearMiss means the answer is off by a small typo and counts as correct.
+	NearMiss
+	// Exact means the answer matches after normalization.
+	Exact
+)
+
+// articles are dropped from the start of an answer, so "to go" matches "go"
+// and "the bank" matches "bank".
+var articles = map[string]bool{
+	"to":  true,
+	"a":   true,
+	"an":  true,
+	"the": true,
+}
+
+// apostrophes lists the characters people type instead of a plain apostrophe,
+// common in Ukrainian words like "м’ята".
+var apostrophes = map[rune]bool{
+	'’': true,
+	'‘': true,
+	'ʼ': true,
+	'`': true,
+	'´': true,
+	'′': true,
+}
+
+// Check compares the given answer with every accepted one and returns the
+// best verdict.
+func Check(given string, accepted ...string) Verdict {
+	got := Normalize(given)
+	if got == "" {
+		return Wrong
+	}
+
+	verdict := Wrong
+	for _, a := range accepted {
+		want := Normalize(a)
+		if want == "" {
+			continue
+		}
+		if got == want {
+			return Exact
+		}
+		if distance(got, want) <= tolerance(want) {
+			verdict = NearMiss
+		}
+	}
+	return verdict
+}
+
+// Normalize brings an answer to a canonical form: composed Unicode, lower
+// case, one kind of apostrophe, ё as е, no punctuation, single spaces and no
+// leading article.
+func Normalize(s string) string {
+	s = norm.NFC.String(s)
+	s = strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		switch {
+		case apostrophes[r]:
+			return '\''
+		case r == 'ё':
+			return 'е'
+		case r == '\'' || r == '-':
+			return r
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			return ' '
+		}
+		return r
+	}, s)
+
+	fields := strings.Fields(s)
+	if len(fields) > 1 && articles[fields[0]] {
+		fields = fields[1:]
+	}
+	return strings.Join(fields, " ")
+}
+
+// tolerance is the number of typos allowed for an answer of this length.
+func tolerance(s string) int {
+	n := len([]rune(s))
+	switch {
+	case n <= 3:
+		return 0
+	case n <= 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// distance is the edit distance between two strings, counting an insertion,
+// a deletion, a substitution or a swap of two adjacent letters as one typo.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
@@ -0,0 +1,72 @@
+package answer
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"  Bank ", "bank"},
+		{"м’ята", "м'ята"},
+		{"м`ята", "м'ята"},
+		{"мʼята", "м'ята"},
+		{"ёлка", "елка"},
+		{"ЁЖ", "еж"},
+		{"to go", "go"},
+		{"the bank", "bank"},
+		{"an apple", "apple"},
+		{"a", "a"}, // a lone article is the answer itself
+		{"to", "to"},
+		{"go to", "go to"},
+		{"well-known", "well-known"},
+		{"hello,   world!", "hello world"},
+		{"e\u0301", "\u00e9"}, // decomposed accent
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		given    string
+		accepted []string
+		want     Verdict
+	}{
+		{"банк", []string{"банк"}, Exact},
+		{"Берег", []string{"банк", "берег"}, Exact},
+		{"м'ята", []string{"м’ята"}, Exact},
+		{"елка", []string{"ёлка"}, Exact},
+		{"go", []string{"to go"}, Exact},
+		{"", []string{"банк"}, Wrong},
+		{"   ", []string{""}, Wrong},
+
+		// Up to 3 letters no typo is allowed.
+		{"кіт", []string{"кит"}, Wrong},
+		{"cat", []string{"cut"}, Wrong},
+		// 4 to 7 letters allow one typo.
+		{"берек", []string{"берег"}, NearMiss},
+		{"бреег", []string{"берег"}, NearMiss}, // swapped letters
+		{"бере", []string{"берег"}, NearMiss},
+		{"брек", []string{"берег"}, Wrong},
+		{"помилкка", []string{"помилка"}, NearMiss},
+		{"помилкаа", []string{"помилка"}, NearMiss},
+		{"поммилкаа", []string{"помилка"}, Wrong},
+		// 8 letters and more allow two.
+		{"нафчатісь", []string{"навчатись"}, NearMiss},
+		{"навчаттисся", []string{"навчатись"}, Wrong},
+
+		// Exact wins over a near miss in another translation.
+		{"берег", []string{"берех", "берег"}, Exact},
+		{"собака", []string{"кіт"}, Wrong},
+	}
+
+	for _, tt := range tests {
+		if got := Check(tt.given, tt.accepted...); got != tt.want {
+			t.Errorf("Check(%q, %q) = %v, want %v", tt.given, tt.accepted, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package bot
+
+import (
+	"english-words-bot/internal/models"
+	"english-words-bot/internal/parser"
+	"english-words-bot/internal/services"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// pendingMerge is a word the user already had with other translations,
+// waiting for the user to decide whether to merge the new ones.
+type pendingMerge struct {
+	wordID       uint
+	englishWord  string
+	translations []string
+	done         bool
+}
+
+// mergeBatch holds the merges offered after one add, with the add summary
+// they are shown under. The token is carried by the merge buttons, so buttons
+// left on an older message do not act on a newer batch.
+type mergeBatch struct {
+	token   string
+	summary string
+	items   []pendingMerge
+}
+
+// handleAddWords adds the words from a message, putting them into the given
+// deck unless deckID is zero.
+func (b *Bot) handleAddWords(c tele.Context, text string, deckID uint) error {
+	userID := c.Sender().ID
+	user, _ := b.userService.GetOrCreateUser(userID, c.Sender().Username)
+
+	var deck *models.Deck
+	if deckID != 0 {
+		var err error
+		if deck, err = b.deckService.GetDeckByID(user.ID, deckID); err != nil {
+			fmt.Printf("Error getting deck %d: %v\n", deckID, err)
+		}
+	}
+	addedCount := 0
+	var duplicates []string
+	var merges []pendingMerge
+	var added *models.Word
+
+	entries, lineErrors := parser.Parse(text)
+	errorCount := len(lineErrors)
+	for _, entry := range entries {
+		result, existing, err := b.wordService.AddWord(user.ID, entry.Headword, entry.Translation)
+		if err == nil && deck != nil {
+			if err := b.deckService.AddWordToDeck(deck, existing); err != nil {
+				fmt.Printf("Error adding word %d to deck %d: %v\n", existing.ID, deck.ID, err)
+			}
+		}
+		switch {
+		case err != nil:
+			fmt.Printf("Error adding word from line %d: %v\n", entry.Line, err)
+			errorCount++
+		case result == services.WordDuplicate:
+			duplicates = append(duplicates, html.EscapeString(existing.EnglishWord))
+		case result == services.WordConflict:
+			merges = append(merges, pendingMerge{
+				wordID:       existing.ID,
+				englishWord:  existing.EnglishWord,
+				translations: models.SplitTranslations(entry.Translation),
+			})
+		default:
+			addedCount++
+			added = existing
+		}
+	}
+
+	delete(b.userStates, userID)
+
+	var response string
+	if addedCount > 0 {
+		response = fmt.Sprintf("Successfully added %d word(s)", addedCount)
+		if deck != nil {
+			response += fmt.Sprintf(" to deck \"%s\"", html.EscapeString(deck.Name))
+		}
+		if errorCount > 0 {
+			response += fmt.Sprintf(", but %d word(s) had errors", errorCount)
+		}
+	} else if len(duplicates) == 0 && len(merges) == 0 {
+		return c.Send("No words were added. Please use the correct format: english_word - translation"+
+			describeLineErrors(lineErrors), &tele.SendOptions{
+			ParseMode: tele.ModeHTML,
+		})
+	} else {
+		response = "No new words were added"
+		if errorCount > 0 {
+			response += fmt.Sprintf(", %d word(s) had errors", errorCount)
+		}
+	}
+
+	if len(duplicates) > 0 {
+		response += fmt.Sprintf("\n\nSkipped duplicates: %s", strings.Join(duplicates, ", "))
+	}
+	response += describeLineErrors(lineErrors)
+	if len(merges) == 0 && addedCount == 1 && errorCount == 0 && len(duplicates) == 0 {
+		// Одне нове слово: пропонуємо додати приклади та нотатку
+		return c.Send(response+"\n\nYou can also add example sentences and a note to it.",
+			detailsMarkup(added, "Add"))
+	}
+	if len(merges) == 0 {
+		return c.Send(response, &tele.SendOptions{
+			ParseMode: tele.ModeHTML,
+		})
+	}
+
+	batch := &mergeBatch{
+		token:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		summary: response,
+		items:   merges,
+	}
+	b.pendingMerges[userID] = batch
+	return c.Send(batch.describe(false), batch.markup())
+}
+
+// maxLineErrors limits the rejected lines listed after an add, to keep the
+// reply within Telegram's message size.
+const maxLineErrors = 20
+
+// describeLineErrors lists the rejected lines with their line numbers.
+func describeLineErrors(errs []*parser.LineError) string {
+	if len(errs) == 0 {
+		return ""
+	}
+
+	var response strings.Builder
+	response.WriteString("\n\nRejected:")
+	for i, err := range errs {
+		if i == maxLineErrors {
+			response.WriteString(fmt.Sprintf("\n...and %d more", len(errs)-maxLineErrors))
+			break
+		}
+		if err.Text == "" {
+			response.WriteString(fmt.Sprintf("\nLine %d: %v", err.Line, err.Err))
+			continue
+		}
+		response.WriteString(fmt.Sprintf("\nLine %d: \"%s\" - %v", err.Line, html.EscapeString(err.Text), err.Err))
+	}
+	return response.String()
+}
+
+// describe shows the add summary and the state of every merge. A final
+// description lists the words left unmerged as skipped.
+func (m *mergeBatch) describe(final bool) string {
+	var pending, merged, skipped []string
+	for _, item := range m.items {
+		switch {
+		case item.done:
+			merged = append(merged, html.EscapeString(item.englishWord))
+		case final:
+			skipped = append(skipped, html.EscapeString(item.englishWord))
+		default:
+			pending = append(pending, fmt.Sprintf("• %s (new: %s)",
+				html.EscapeString(item.englishWord), escapeJoin(item.translations)))
+		}
+	}
+
+	response := m.summary
+	if len(pending) > 0 {
+		response += "\n\nAlready in your dictionary with a different translation:\n" +
+			strings.Join(pending, "\n") +
+			"\n\nMerge the new translations into the existing words?"
+	}
+	if len(merged) > 0 {
+		response += fmt.Sprintf("\n\nMerged: %s", strings.Join(merged, ", "))
+	}
+	if len(skipped) > 0 {
+		response += fmt.Sprintf("\n\nNot merged: %s", strings.Join(skipped, ", "))
+	}
+	return response
+}
+
+// markup offers a merge button per pending word plus "all" and "skip".
+func (m *mergeBatch) markup() *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+	var rows []tele.Row
+	for _, item := range m.items {
+		if !item.done {
+			id := strconv.FormatUint(uint64(item.wordID), 10)
+			rows = append(rows, menu.Row(menu.Data("➕ Merge "+item.englishWord, "merge", m.token, id)))
+		}
+	}
+	if len(rows) > 1 {
+		rows = append(rows, menu.Row(menu.Data("➕ Merge all", "merge", m.token, "all")))
+	}
+	rows = append(rows, menu.Row(menu.Data("⏭ Skip", "merge", m.token, "skip")))
+	menu.Inline(rows...)
+	return menu
+}
+
+// handleMerge merges one or all pending translations, or skips the rest.
+func (b *Bot) handleMerge(c tele.Context) error {
+	userID := c.Sender().ID
+	token, data, _ := strings.Cut(c.Data(), "|")
+	batch, ok := b.pendingMerges[userID]
+	if !ok || batch.token != token {
+		return c.Respond(&tele.CallbackResponse{Text: "This offer has expired"})
+	}
+
+	user, _ := b.userService.GetOrCreateUser(userID, c.Sender().Username)
+	pending := false
+	for i := range batch.items {
+		item := &batch.items[i]
+		id := strconv.FormatUint(uint64(item.wordID), 10)
+		if !item.done && (data == "all" || data == id) {
+			if _, err := b.wordService.MergeTranslations(user.ID, item.wordID, item.translations); err != nil {
+				fmt.Printf("Error merging translations into word %d: %v\n", item.wordID, err)
+			} else {
+				item.done = true
+			}
+		}
+		pending = pending || !item.done
+	}
+
+	var err error
+	if data == "skip" || !pending {
+		delete(b.pendingMerges, userID)
+		err = c.Edit(batch.describe(true))
+	} else {
+		err = c.Edit(batch.describe(false), batch.markup())
+	}
+	if err != nil {
+		fmt.Printf("Error editing merge message: %v\n", err)
+	}
+	return c.Respond()
+}
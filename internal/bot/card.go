@@ -0,0 +1,193 @@
+package bot
+
+import (
+	"english-words-bot/internal/models"
+	"english-words-bot/internal/services"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// renderWordCard builds the detail view of a word with its examples and note.
+func renderWordCard(word *models.Word) (string, *tele.ReplyMarkup) {
+	var card strings.Builder
+	card.WriteString(fmt.Sprintf("<b>%s</b> - %s\n",
+		html.EscapeString(word.Headword().String()), html.EscapeString(word.Translation)))
+
+	if examples := word.ExampleList(); len(examples) > 0 {
+		card.WriteString("\n💬 Examples:\n")
+		for _, example := range examples {
+			card.WriteString("• " + html.EscapeString(example) + "\n")
+		}
+	}
+	if word.Note != "" {
+		card.WriteString("\n🗒 Note: " + html.EscapeString(word.Note) + "\n")
+	}
+
+	return card.String(), detailsMarkup(word, "Edit")
+}
+
+// detailsMarkup offers to change each field of a word on its own. The verb
+// labels the examples and note buttons: "Add" right after adding the word,
+// "Edit" later.
+func detailsMarkup(word *models.Word, verb string) *tele.ReplyMarkup {
+	id := strconv.FormatUint(uint64(word.ID), 10)
+	menu := &tele.ReplyMarkup{}
+	menu.Inline(
+		menu.Row(
+			menu.Data("🔤 Edit English", "word_english", id),
+			menu.Data("🌐 Edit translation", "word_translation", id),
+		),
+		menu.Row(
+			menu.Data("💬 "+verb+" examples", "word_examples", id),
+			menu.Data("🗒 "+verb+" note", "word_note", id),
+		),
+	)
+	return menu
+}
+
+// exampleHint shows the examples of a word after a wrong answer.
+func exampleHint(word *models.Word) string {
+	var hint strings.Builder
+	for _, example := range word.ExampleList() {
+		hint.WriteString("\n💬 " + html.EscapeString(example))
+	}
+	return hint.String()
+}
+
+func (b *Bot) handleWordCard(c tele.Context) error {
+	word, _, err := b.getCallbackWord(c)
+	if err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Word not found"})
+	}
+
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Error answering callback: %v\n", err)
+	}
+	text, markup := renderWordCard(word)
+	return c.Send(text, markup)
+}
+
+// Fields of a word that can be changed one at a time. The names are used in
+// the callback data and in the "waiting_for_word_<field>_<id>" states.
+const (
+	fieldEnglish     = "english"
+	fieldTranslation = "translation"
+	fieldExamples    = "examples"
+	fieldNote        = "note"
+)
+
+// fieldPrompts ask for the new value of a field, given the English word and,
+// for the English word and the translation, the current value.
+var fieldPrompts = map[string]string{
+	fieldEnglish: "Send the new English word for \"%s\".\n" +
+		"A transcription and a part of speech are optional: record /ˈrek.ɔːd/ (n.)\n\n" +
+		"Now: <code>%s</code>",
+	fieldTranslation: "Send the new translation of \"%s\".\n" +
+		"Separate several translations with a semicolon.\n\n" +
+		"Now: <code>%s</code>",
+	fieldExamples: "Send example sentences for \"%s\", one per line.\n" +
+		"Send - to remove the examples.",
+	fieldNote: "Send a note for \"%s\".\n" +
+		"Send - to remove the note.",
+}
+
+// handleWordField returns the handler of a button that asks for a new value
+// of one field of a word.
+func (b *Bot) handleWordField(field string) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		word, _, err := b.getCallbackWord(c)
+		if err != nil {
+			return c.Respond(&tele.CallbackResponse{Text: "Word not found"})
+		}
+
+		current := word.Translation
+		if field == fieldEnglish {
+			current = word.Headword().String()
+		}
+
+		b.userStates[c.Sender().ID] = fmt.Sprintf("waiting_for_word_%s_%d", field, word.ID)
+		if err := c.Respond(); err != nil {
+			fmt.Printf("Error answering callback: %v\n", err)
+		}
+		prompt := fieldPrompts[field]
+		if field == fieldExamples || field == fieldNote {
+			return c.Send(fmt.Sprintf(prompt, html.EscapeString(word.EnglishWord)))
+		}
+		return c.Send(fmt.Sprintf(prompt, html.EscapeString(word.EnglishWord), html.EscapeString(current)))
+	}
+}
+
+// parseFieldState reads the field and the word ID out of a
+// "waiting_for_word_<field>_<id>" state.
+func parseFieldState(state string) (string, uint, bool) {
+	rest, ok := strings.CutPrefix(state, "waiting_for_word_")
+	if !ok {
+		return "", 0, false
+	}
+	field, idText, ok := strings.Cut(rest, "_")
+	if _, known := fieldPrompts[field]; !ok || !known {
+		return "", 0, false
+	}
+	wordID, err := strconv.ParseUint(idText, 10, 32)
+	if err != nil {
+		return "", 0, false
+	}
+	return field, uint(wordID), true
+}
+
+// handleWordFieldValue saves the value typed for one field of a word and shows
+// the updated word card.
+func (b *Bot) handleWordFieldValue(c tele.Context, field string, wordID uint, text string) error {
+	userID := c.Sender().ID
+	user, _ := b.userService.GetOrCreateUser(userID, c.Sender().Username)
+
+	text = strings.TrimSpace(text)
+	var err error
+	switch field {
+	case fieldEnglish:
+		headword := models.ParseHeadword(text)
+		if headword.EnglishWord == "" {
+			return c.Send("The English word can't be empty. Please send it again.")
+		}
+		err = b.wordService.UpdateHeadword(user.ID, wordID, headword)
+	case fieldTranslation:
+		if len(models.SplitTranslations(text)) == 0 {
+			return c.Send("The translation can't be empty. Please send it again.")
+		}
+		err = b.wordService.UpdateTranslation(user.ID, wordID, text)
+	case fieldExamples, fieldNote:
+		if text == "-" {
+			text = ""
+		}
+		if field == fieldNote {
+			err = b.wordService.UpdateNote(user.ID, wordID, text)
+		} else {
+			err = b.wordService.UpdateExamples(user.ID, wordID, models.SplitExamples(text))
+		}
+	}
+	if errors.Is(err, services.ErrWordNotFound) {
+		delete(b.userStates, userID)
+		return c.Send("Word not found")
+	}
+	if err != nil {
+		return c.Send("Error updating word")
+	}
+
+	delete(b.userStates, userID)
+	return b.sendUpdatedCard(c, user.ID, wordID)
+}
+
+// sendUpdatedCard shows the card of a word that was just changed.
+func (b *Bot) sendUpdatedCard(c tele.Context, userID, wordID uint) error {
+	word, err := b.wordService.GetWordByID(userID, wordID)
+	if err != nil {
+		return c.Send("Word updated successfully!")
+	}
+	card, markup := renderWordCard(word)
+	return c.Send("✅ Word updated\n\n"+card, markup)
+}
@@ -0,0 +1,215 @@
+package bot
+
+import (
+	"english-words-bot/internal/services"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+const decksHelp = "Commands:\n" +
+	"/newdeck name - create a deck\n" +
+	"/renamedeck old name -> new name - rename a deck\n" +
+	"/deletedeck name - delete a deck (its words stay in your dictionary)"
+
+func (b *Bot) handleDecks(c tele.Context) error {
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	decks, err := b.deckService.GetUserDeckInfos(user.ID)
+	if err != nil {
+		return c.Send("Error getting decks")
+	}
+
+	if len(decks) == 0 {
+		return c.Send("You don't have any decks yet.\n\n" + decksHelp)
+	}
+
+	var response strings.Builder
+	response.WriteString("Your decks:\n\n")
+	for _, deck := range decks {
+		mark := ""
+		if deck.Train {
+			mark = " 🎯"
+		}
+		response.WriteString(fmt.Sprintf("• %s (%d words)%s\n", html.EscapeString(deck.Name), deck.WordCount, mark))
+	}
+	response.WriteString("\n🎯 - selected for training\n\n" + decksHelp)
+	return c.Send(response.String())
+}
+
+func (b *Bot) handleNewDeck(c tele.Context) error {
+	name := strings.TrimSpace(c.Message().Payload)
+	if name == "" {
+		return c.Send("Please use format: /newdeck name")
+	}
+
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	deck, err := b.deckService.CreateDeck(user.ID, name)
+	if errors.Is(err, services.ErrDeckExists) {
+		return c.Send(fmt.Sprintf("You already have a deck \"%s\"", html.EscapeString(name)))
+	}
+	if err != nil {
+		return c.Send("Error creating deck")
+	}
+	return c.Send(fmt.Sprintf("Deck \"%s\" created", html.EscapeString(deck.Name)))
+}
+
+func (b *Bot) handleRenameDeck(c tele.Context) error {
+	oldName, newName, ok := strings.Cut(c.Message().Payload, "->")
+	oldName, newName = strings.TrimSpace(oldName), strings.TrimSpace(newName)
+	if !ok || oldName == "" || newName == "" {
+		return c.Send("Please use format: /renamedeck old name -> new name")
+	}
+
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	deck, err := b.deckService.RenameDeck(user.ID, oldName, newName)
+	switch {
+	case errors.Is(err, services.ErrDeckNotFound):
+		return c.Send(fmt.Sprintf("Deck \"%s\" not found", html.EscapeString(oldName)))
+	case errors.Is(err, services.ErrDeckExists):
+		return c.Send(fmt.Sprintf("You already have a deck \"%s\"", html.EscapeString(newName)))
+	case err != nil:
+		return c.Send("Error renaming deck")
+	}
+	return c.Send(fmt.Sprintf("Deck renamed to \"%s\"", html.EscapeString(deck.Name)))
+}
+
+func (b *Bot) handleDeleteDeck(c tele.Context) error {
+	name := strings.TrimSpace(c.Message().Payload)
+	if name == "" {
+		return c.Send("Please use format: /deletedeck name")
+	}
+
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	deck, err := b.deckService.DeleteDeck(user.ID, name)
+	if errors.Is(err, services.ErrDeckNotFound) {
+		return c.Send(fmt.Sprintf("Deck \"%s\" not found", html.EscapeString(name)))
+	}
+	if err != nil {
+		return c.Send("Error deleting deck")
+	}
+	return c.Send(fmt.Sprintf("Deck \"%s\" deleted. Its words are still in your dictionary.", html.EscapeString(deck.Name)))
+}
+
+// sendAddDeckChoice lets the user pick the deck new words go to.
+func (b *Bot) sendAddDeckChoice(c tele.Context) error {
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	decks, err := b.deckService.GetUserDecks(user.ID)
+	if err != nil || len(decks) == 0 {
+		return err
+	}
+
+	menu := &tele.ReplyMarkup{}
+	var rows []tele.Row
+	for _, deck := range decks {
+		rows = append(rows, menu.Row(menu.Data("🗂 "+deck.Name, "add_deck", strconv.FormatUint(uint64(deck.ID), 10))))
+	}
+	rows = append(rows, menu.Row(menu.Data("No deck", "add_deck", "0")))
+	menu.Inline(rows...)
+
+	return c.Send("New words go to no deck. Choose a deck to add them to:", menu)
+}
+
+func (b *Bot) handleAddDeck(c tele.Context) error {
+	userID := c.Sender().ID
+	state := b.userStates[userID]
+	if state != "waiting_for_word" && !strings.HasPrefix(state, "waiting_for_word_deck_") {
+		return c.Respond(&tele.CallbackResponse{Text: "Press ➕ Add Word first"})
+	}
+
+	deckID, _ := strconv.ParseUint(c.Data(), 10, 32)
+	if deckID == 0 {
+		b.userStates[userID] = "waiting_for_word"
+		if err := c.Edit("New words go to no deck."); err != nil {
+			fmt.Printf("Error editing deck choice: %v\n", err)
+		}
+		return c.Respond()
+	}
+
+	user, _ := b.userService.GetOrCreateUser(userID, c.Sender().Username)
+	deck, err := b.deckService.GetDeckByID(user.ID, uint(deckID))
+	if err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Deck not found"})
+	}
+
+	b.userStates[userID] = fmt.Sprintf("waiting_for_word_deck_%d", deck.ID)
+	if err := c.Edit(fmt.Sprintf("New words go to deck \"%s\".", html.EscapeString(deck.Name))); err != nil {
+		fmt.Printf("Error editing deck choice: %v\n", err)
+	}
+	return c.Respond()
+}
+
+// renderTrainingDecks builds the deck selection for training.
+func (b *Bot) renderTrainingDecks(userID uint) (string, *tele.ReplyMarkup, error) {
+	decks, err := b.deckService.GetUserDecks(userID)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(decks) == 0 {
+		return "You don't have any decks yet, training uses all your words.\n\n" + decksHelp, nil, nil
+	}
+
+	menu := &tele.ReplyMarkup{}
+	var rows []tele.Row
+	var selected []string
+	for _, deck := range decks {
+		label := "⬜ " + deck.Name
+		if deck.Train {
+			label = "✅ " + deck.Name
+			selected = append(selected, html.EscapeString(deck.Name))
+		}
+		rows = append(rows, menu.Row(menu.Data(label, "deck_train", strconv.FormatUint(uint64(deck.ID), 10))))
+	}
+	rows = append(rows, menu.Row(menu.Data("All words", "deck_train", "all")))
+	menu.Inline(rows...)
+
+	text := "Training uses all your words."
+	if len(selected) > 0 {
+		text = fmt.Sprintf("Training uses decks: %s.", strings.Join(selected, ", "))
+	}
+	return text + "\nTap a deck to select or deselect it:", menu, nil
+}
+
+func (b *Bot) handleTrainingDecks(c tele.Context) error {
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	text, markup, err := b.renderTrainingDecks(user.ID)
+	if err != nil {
+		return c.Send("Error getting decks")
+	}
+	if markup == nil {
+		return c.Send(text)
+	}
+	return c.Send(text, markup)
+}
+
+func (b *Bot) handleDeckTrain(c tele.Context) error {
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+
+	var err error
+	if c.Data() == "all" {
+		err = b.deckService.ClearTraining(user.ID)
+	} else {
+		deckID, _ := strconv.ParseUint(c.Data(), 10, 32)
+		_, err = b.deckService.ToggleTraining(user.ID, uint(deckID))
+	}
+	if err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Error updating decks"})
+	}
+
+	text, markup, err := b.renderTrainingDecks(user.ID)
+	if err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Error getting decks"})
+	}
+	if markup == nil {
+		err = c.Edit(text)
+	} else {
+		err = c.Edit(text, markup)
+	}
+	if err != nil {
+		fmt.Printf("Error editing deck selection: %v\n", err)
+	}
+	return c.Respond()
+}
@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"english-words-bot/internal/exporter"
+	"english-words-bot/internal/models"
+	"fmt"
+	"io"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// formatNames are the button labels for exporter.Formats.
+var formatNames = map[string]string{
+	exporter.FormatCSV:  "CSV",
+	exporter.FormatJSON: "JSON",
+	exporter.FormatAnki: "Anki",
+}
+
+// handleExport sends the dictionary in the format given after the command,
+// or asks for the format.
+func (b *Bot) handleExport(c tele.Context) error {
+	format := strings.ToLower(strings.TrimSpace(c.Message().Payload))
+	if _, ok := formatNames[format]; ok {
+		return b.sendExport(c, format)
+	}
+
+	menu := &tele.ReplyMarkup{}
+	var row tele.Row
+	for _, format := range exporter.Formats {
+		row = append(row, menu.Data(formatNames[format], "export", format))
+	}
+	menu.Inline(row)
+	return c.Send("Choose the export format:\n\n"+
+		"CSV - spreadsheets, can be imported back into the bot\n"+
+		"JSON - everything including learning progress, for other programs\n"+
+		"Anki - text file for File > Import in Anki", menu)
+}
+
+func (b *Bot) handleExportFormat(c tele.Context) error {
+	format := c.Data()
+	if _, ok := formatNames[format]; !ok {
+		return c.Respond(&tele.CallbackResponse{Text: "Unknown format"})
+	}
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Error answering callback: %v\n", err)
+	}
+	return b.sendExport(c, format)
+}
+
+// sendExport starts sending the user's words as a document. Updates are
+// handled one at a time, so the upload runs in the background and does not
+// hold up other users.
+func (b *Bot) sendExport(c tele.Context, format string) error {
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	if _, total, err := b.wordService.GetUserWordsPage(user.ID, models.WordListView{}, 0, 1); err != nil {
+		return c.Send("Error getting words")
+	} else if total == 0 {
+		return c.Send("You don't have any words to export yet.")
+	}
+
+	go b.uploadExport(c, user.ID, format)
+	return nil
+}
+
+// uploadExport streams the user's words as a document, writing them while
+// the file is being uploaded. It must not touch the session maps.
+func (b *Bot) uploadExport(c tele.Context, userID uint, format string) {
+	reader, writer := io.Pipe()
+	go func() {
+		err := exporter.Write(writer, format, func(fn func(*models.Word) error) error {
+			return b.wordService.EachWord(userID, fn)
+		})
+		writer.CloseWithError(err)
+	}()
+	defer reader.Close()
+
+	err := c.Send(&tele.Document{
+		File:     tele.FromReader(reader),
+		FileName: exporter.FileName(format),
+		Caption:  fmt.Sprintf("Your dictionary (%s)", formatNames[format]),
+	})
+	if err != nil {
+		fmt.Printf("Error exporting words for user %d: %v\n", userID, err)
+		if err := c.Send("Error exporting words"); err != nil {
+			fmt.Printf("Error sending message: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package bot
+
+import (
+	"english-words-bot/internal/importer"
+	"english-words-bot/internal/models"
+	"english-words-bot/internal/services"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// maxImportSize limits the size of an uploaded file; Telegram does not let
+// bots download larger ones.
+const maxImportSize = 20 << 20
+
+// importPreviewRows is how many rows are shown before importing.
+const importPreviewRows = 5
+
+// pendingImport is an uploaded word list waiting for the user to confirm it.
+type pendingImport struct {
+	fileName string
+	format   string
+	sides    [2]string // fields read as the English word and the translation
+	entries  []services.ImportEntry
+	invalid  int
+	problems string // the rejected rows, ready to show
+	swapped  bool   // the two fields are imported the other way round
+}
+
+// handleDocument reads an uploaded word list and shows a preview of it.
+func (b *Bot) handleDocument(c tele.Context) error {
+	doc := c.Message().Document
+	ext := strings.ToLower(filepath.Ext(doc.FileName))
+	switch ext {
+	case ".csv", ".tsv", ".txt", ".apkg":
+	default:
+		return c.Send("Please send a .csv, .tsv or .txt file with your words, or an Anki .apkg package")
+	}
+	if doc.FileSize > maxImportSize {
+		return c.Send(fmt.Sprintf("The file is too large. Please send files up to %d MB.", maxImportSize>>20))
+	}
+
+	reader, err := b.bot.File(&doc.File)
+	if err != nil {
+		fmt.Printf("Error downloading file %s: %v\n", doc.FileName, err)
+		return c.Send("Error downloading the file")
+	}
+	defer reader.Close()
+
+	var file *importer.File
+	if ext == ".apkg" {
+		file, err = readApkg(io.LimitReader(reader, maxImportSize))
+	} else {
+		file, err = importer.ReadDelimited(io.LimitReader(reader, maxImportSize), ext)
+	}
+	switch {
+	case errors.Is(err, importer.ErrEmpty):
+		return c.Send("The file is empty")
+	case errors.Is(err, importer.ErrTooLarge):
+		return c.Send("This Anki package is too large to import.")
+	case errors.Is(err, importer.ErrAnkiFormat):
+		return c.Send("Cannot read this Anki package. Please export it again with " +
+			"\"Support older Anki versions\" turned on.")
+	case err != nil:
+		fmt.Printf("Error reading file %s: %v\n", doc.FileName, err)
+		return c.Send("Error reading the file")
+	}
+
+	format := file.Format
+	if file.Header {
+		format += ", with a header row"
+	}
+	entries := make([]services.ImportEntry, 0, len(file.Rows))
+	for _, row := range file.Rows {
+		entries = append(entries, services.ImportEntry{
+			Headword:    row.Headword,
+			Translation: row.Translation,
+			Note:        row.Note,
+			Decks:       row.Decks,
+		})
+	}
+
+	userID := c.Sender().ID
+	problems := describeLineErrors(file.Errors)
+	if len(entries) == 0 {
+		delete(b.pendingImports, userID)
+		return c.Send("No words found in " + html.EscapeString(doc.FileName) + problems)
+	}
+
+	pending := &pendingImport{
+		fileName: doc.FileName,
+		format:   format,
+		sides:    file.Sides,
+		entries:  entries,
+		invalid:  len(file.Errors),
+		problems: problems,
+	}
+	b.pendingImports[userID] = pending
+	text, markup := pending.preview()
+	return c.Send(text, markup)
+}
+
+// readApkg saves an uploaded Anki package to a temporary file, which the
+// importer needs to open it as a zip archive.
+func readApkg(r io.Reader) (*importer.File, error) {
+	tmp, err := os.CreateTemp("", "import-*.apkg")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	return importer.ReadApkg(tmp.Name())
+}
+
+// importEntries returns the entries to import, with the fields swapped if
+// the user asked for it.
+func (p *pendingImport) importEntries() []services.ImportEntry {
+	if !p.swapped {
+		return p.entries
+	}
+
+	swapped := make([]services.ImportEntry, len(p.entries))
+	for i, entry := range p.entries {
+		entry.Headword, entry.Translation = models.ParseHeadword(entry.Translation),
+			models.JoinTranslations(models.SplitTranslations(entry.Headword.String()))
+		swapped[i] = entry
+	}
+	return swapped
+}
+
+// preview shows the field mapping and the first entries of an import, with
+// buttons to swap the fields, confirm or cancel.
+func (p *pendingImport) preview() (string, *tele.ReplyMarkup) {
+	english, translation := p.sides[0], p.sides[1]
+	if p.swapped {
+		english, translation = translation, english
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("📥 %s (%s)\n\n", html.EscapeString(p.fileName), p.format))
+	response.WriteString(html.EscapeString(fmt.Sprintf("%s → English word\n%s → Translation\n\nFirst rows:\n", english, translation)))
+
+	entries := p.importEntries()
+	for i, entry := range entries {
+		if i == importPreviewRows {
+			break
+		}
+		response.WriteString(html.EscapeString(fmt.Sprintf("%d. %s - %s", i+1, entry.Headword, entry.Translation)))
+		if entry.Note != "" {
+			response.WriteString(html.EscapeString(" 🗒 " + entry.Note))
+		}
+		if len(entry.Decks) > 0 {
+			response.WriteString(html.EscapeString(" 🗂 " + strings.Join(entry.Decks, ", ")))
+		}
+		response.WriteString("\n")
+	}
+	response.WriteString(fmt.Sprintf("\n%d word(s) ready to import, %d invalid row(s)", len(entries), p.invalid))
+	response.WriteString(p.problems)
+
+	menu := &tele.ReplyMarkup{}
+	menu.Inline(
+		menu.Row(menu.Data("🔄 Swap fields", "import", "swap")),
+		menu.Row(
+			menu.Data(fmt.Sprintf("✅ Import %d word(s)", len(entries)), "import", "yes"),
+			menu.Data("❌ Cancel", "import", "no"),
+		),
+	)
+	return response.String(), menu
+}
+
+// handleImport swaps the fields of the pending word list, imports it or
+// drops it.
+func (b *Bot) handleImport(c tele.Context) error {
+	userID := c.Sender().ID
+	pending, ok := b.pendingImports[userID]
+	if !ok {
+		return c.Respond(&tele.CallbackResponse{Text: "Nothing to import. Please send the file again."})
+	}
+
+	switch c.Data() {
+	case "swap":
+		pending.swapped = !pending.swapped
+		text, markup := pending.preview()
+		if err := c.Edit(text, markup); err != nil {
+			fmt.Printf("Error editing import message: %v\n", err)
+		}
+		return c.Respond()
+
+	case "yes":
+		delete(b.pendingImports, userID)
+
+	default:
+		delete(b.pendingImports, userID)
+		if err := c.Edit("Import cancelled"); err != nil {
+			fmt.Printf("Error editing import message: %v\n", err)
+		}
+		return c.Respond()
+	}
+
+	user, _ := b.userService.GetOrCreateUser(userID, c.Sender().Username)
+	result, err := b.wordService.ImportWords(user.ID, pending.importEntries())
+	if err != nil {
+		fmt.Printf("Error importing words for user %d: %v\n", user.ID, err)
+		if err := c.Edit("Error importing words, nothing was imported"); err != nil {
+			fmt.Printf("Error editing import message: %v\n", err)
+		}
+		return c.Respond()
+	}
+
+	if err := c.Edit(fmt.Sprintf("Import finished!\n\n✅ Added: %d\n⏭ Duplicates skipped: %d\n⚠️ Invalid rows: %d",
+		result.Added, result.Duplicates, pending.invalid)); err != nil {
+		fmt.Printf("Error editing import message: %v\n", err)
+	}
+	return c.Respond()
+}
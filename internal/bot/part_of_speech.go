@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"english-words-bot/internal/models"
+	"fmt"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// partOfSpeechNames are the button labels for models.PartsOfSpeech.
+var partOfSpeechNames = map[string]string{
+	"n.":      "Nouns",
+	"v.":      "Verbs",
+	"adj.":    "Adjectives",
+	"adv.":    "Adverbs",
+	"prep.":   "Prepositions",
+	"pron.":   "Pronouns",
+	"conj.":   "Conjunctions",
+	"interj.": "Interjections",
+	"phr.":    "Phrases",
+}
+
+// renderPartOfSpeechChoice builds the part-of-speech filter for training with
+// the current choice marked.
+func renderPartOfSpeechChoice(current string) (string, *tele.ReplyMarkup) {
+	menu := &tele.ReplyMarkup{}
+	label := func(pos, name string) string {
+		if pos == current {
+			return "✅ " + name
+		}
+		return name
+	}
+
+	rows := []tele.Row{menu.Row(menu.Data(label("", "All words"), "train_pos", "all"))}
+	var row tele.Row
+	for _, pos := range models.PartsOfSpeech {
+		row = append(row, menu.Data(label(pos, partOfSpeechNames[pos]), "train_pos", pos))
+		if len(row) == 3 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	menu.Inline(rows...)
+
+	text := "Training uses words of every part of speech."
+	if current != "" {
+		text = fmt.Sprintf("Training uses only %s (%s).", partOfSpeechNames[current], current)
+	}
+	return text + "\nWords without a part of speech are left out when one is chosen.", menu
+}
+
+func (b *Bot) handleTrainingPartOfSpeech(c tele.Context) error {
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	text, markup := renderPartOfSpeechChoice(user.TrainingPartOfSpeech)
+	return c.Send(text, markup)
+}
+
+func (b *Bot) handleTrainPartOfSpeech(c tele.Context) error {
+	pos, ok := models.NormalizePartOfSpeech(c.Data())
+	if !ok {
+		pos = ""
+	}
+
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	if err := b.userService.SetTrainingPartOfSpeech(user.ID, pos); err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Error saving the choice"})
+	}
+
+	text, markup := renderPartOfSpeechChoice(pos)
+	if err := c.Edit(text, markup); err != nil {
+		fmt.Printf("Error editing part of speech choice: %v\n", err)
+	}
+	return c.Respond()
+}
@@ -0,0 +1,184 @@
+package bot
+
+import (
+	"english-words-bot/internal/models"
+	"english-words-bot/internal/services"
+	"english-words-bot/internal/srs"
+	"errors"
+	"fmt"
+	"html"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+const (
+	quizOptions = 4
+	// quizCandidates is how many of the most similar words the distractors
+	// are drawn from, so the same word does not always get the same options.
+	quizCandidates = 6
+	// quizPool is how many words of a similar length are loaded to pick the
+	// candidates from.
+	quizPool = 40
+)
+
+// getQuizMarkup builds the answer buttons for a quiz question: the correct
+// answer and distractors taken from the user's own dictionary.
+func (b *Bot) getQuizMarkup(word *models.Word, reversed bool) (*tele.ReplyMarkup, error) {
+	_, answer := question(word, reversed)
+	words, err := b.wordService.GetQuizCandidates(word.UserID, word.ID, reversed,
+		utf8.RuneCountInString(answer), quizPool)
+	if err != nil {
+		return nil, err
+	}
+
+	options := append([]models.Word{*word}, pickDistractors(word, words, reversed)...)
+	rand.Shuffle(len(options), func(i, j int) {
+		options[i], options[j] = options[j], options[i]
+	})
+
+	menu := &tele.ReplyMarkup{}
+	rows := make([]tele.Row, 0, len(options))
+	for i := range options {
+		_, answer := question(&options[i], reversed)
+		rows = append(rows, menu.Row(menu.Data(answer, "quiz",
+			strconv.FormatUint(uint64(word.ID), 10),
+			strconv.FormatUint(uint64(options[i].ID), 10))))
+	}
+	menu.Inline(rows...)
+
+	return menu, nil
+}
+
+// pickDistractors chooses wrong answers that look like the right one: the same
+// script and a similar length.
+func pickDistractors(word *models.Word, words []models.Word, reversed bool) []models.Word {
+	_, answer := question(word, reversed)
+	answerScript := script(answer)
+	answerLen := utf8.RuneCountInString(answer)
+
+	seen := map[string]bool{strings.ToLower(answer): true}
+	candidates := make([]models.Word, 0, len(words))
+	for _, w := range words {
+		_, option := question(&w, reversed)
+		key := strings.ToLower(option)
+		if w.ID == word.ID || seen[key] {
+			continue
+		}
+		seen[key] = true
+		candidates = append(candidates, w)
+	}
+
+	score := func(w *models.Word) int {
+		_, option := question(w, reversed)
+		diff := utf8.RuneCountInString(option) - answerLen
+		if diff < 0 {
+			diff = -diff
+		}
+		if script(option) != answerScript {
+			diff += 100
+		}
+		return diff
+	}
+
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return score(&candidates[i]) < score(&candidates[j])
+	})
+
+	if len(candidates) > quizCandidates {
+		candidates = candidates[:quizCandidates]
+	}
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	if len(candidates) > quizOptions-1 {
+		candidates = candidates[:quizOptions-1]
+	}
+	return candidates
+}
+
+// script reports the writing system of the first letter in s.
+func script(s string) string {
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			return "latin"
+		case unicode.Is(unicode.Cyrillic, r):
+			return "cyrillic"
+		case unicode.IsLetter(r):
+			return "other"
+		}
+	}
+	return ""
+}
+
+// handleQuizAnswer scores a tapped quiz option and edits the question message
+// to show whether it was right.
+func (b *Bot) handleQuizAnswer(c tele.Context) error {
+	userID := c.Sender().ID
+	parts := strings.Split(c.Data(), "|")
+	if len(parts) != 2 {
+		return c.Respond()
+	}
+	questionID, _ := strconv.ParseUint(parts[0], 10, 32)
+	chosenID, _ := strconv.ParseUint(parts[1], 10, 32)
+
+	stats, ok := b.trainingStats[userID]
+	if b.userStates[userID] != "training_"+modeQuiz || !ok || b.trainingWords[userID] != uint(questionID) {
+		return c.Respond(&tele.CallbackResponse{Text: "This question is no longer active"})
+	}
+
+	user, _ := b.userService.GetOrCreateUser(userID, c.Sender().Username)
+	word, err := b.wordService.GetWordByID(user.ID, uint(questionID))
+	if errors.Is(err, services.ErrWordNotFound) {
+		if err := c.Edit(deletedWordNote); err != nil {
+			fmt.Printf("Error editing quiz message: %v\n", err)
+		}
+		if err := c.Respond(); err != nil {
+			fmt.Printf("Error answering callback: %v\n", err)
+		}
+		return b.advanceTraining(c, modeQuiz, stats, "")
+	}
+	if err != nil {
+		fmt.Printf("Error getting word for training: %v\n", err)
+		return c.Respond(&tele.CallbackResponse{Text: "Error getting word for training"})
+	}
+
+	prompt, expected := question(word, stats.reversed)
+	var result string
+	chosen := expected
+	quality := srs.QualityWrong
+	if uint(chosenID) == word.ID {
+		stats.correct++
+		quality = srs.QualityPerfect
+		result = fmt.Sprintf("Translate this word: %s\n\n✅ %s — correct! 🎉",
+			html.EscapeString(prompt), html.EscapeString(expected))
+	} else {
+		stats.incorrect++
+		chosen = "?"
+		if chosenWord, err := b.wordService.GetWordByID(user.ID, uint(chosenID)); err == nil {
+			_, chosen = question(chosenWord, stats.reversed)
+		}
+		result = fmt.Sprintf("Translate this word: %s\n\n❌ %s — wrong. The correct answer is: %s",
+			html.EscapeString(prompt), html.EscapeString(chosen), html.EscapeString(expected))
+		result += exampleHint(word)
+	}
+	result += b.recordAnswer(modeQuiz, word, stats, chosen, quality)
+
+	if err := c.Edit(result); err != nil {
+		fmt.Printf("Error editing quiz message: %v\n", err)
+	}
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Error answering callback: %v\n", err)
+	}
+
+	return b.advanceTraining(c, modeQuiz, stats, "")
+}
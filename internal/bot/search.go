@@ -0,0 +1,32 @@
+package bot
+
+import (
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// handleFind searches the dictionary for the text after /find, or asks for
+// it when the command comes alone.
+func (b *Bot) handleFind(c tele.Context) error {
+	if query := strings.TrimSpace(c.Message().Payload); query != "" {
+		return b.sendSearchResults(c, query)
+	}
+	return b.askSearchQuery(c)
+}
+
+func (b *Bot) askSearchQuery(c tele.Context) error {
+	b.userStates[c.Sender().ID] = "waiting_for_search_query"
+	return c.Send("Send a word or part of it to search in English words and translations")
+}
+
+// sendSearchResults remembers the query for paging and sends the first page
+// of matches. An empty query would match every word, so it is asked again.
+func (b *Bot) sendSearchResults(c tele.Context, query string) error {
+	if query == "" {
+		return b.askSearchQuery(c)
+	}
+	delete(b.userStates, c.Sender().ID)
+	b.searchQueries[c.Sender().ID] = query
+	return b.sendWordsPage(c, listSearch)
+}
@@ -0,0 +1,90 @@
+package bot
+
+import (
+	"english-words-bot/internal/models"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// persistSession saves the sender's session after every update, so a restart
+// does not drop in-progress training or edit flows.
+func (b *Bot) persistSession(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		err := next(c)
+		if c.Sender() != nil {
+			b.saveSession(c.Sender().ID)
+		}
+		return err
+	}
+}
+
+func (b *Bot) saveSession(userID int64) {
+	state, hasState := b.userStates[userID]
+	stats, hasStats := b.trainingStats[userID]
+	if !hasState && !hasStats {
+		if err := b.sessionService.DeleteSession(userID); err != nil {
+			fmt.Printf("Error deleting session for user %d: %v\n", userID, err)
+		}
+		return
+	}
+
+	ids := make([]string, len(stats.words))
+	for i, id := range stats.words {
+		ids[i] = strconv.FormatUint(uint64(id), 10)
+	}
+
+	session := models.Session{
+		TelegramID:   userID,
+		State:        state,
+		WordID:       b.trainingWords[userID],
+		WordIDs:      strings.Join(ids, ","),
+		CurrentIndex: stats.currentIndex,
+		Correct:      stats.correct,
+		Incorrect:    stats.incorrect,
+		Direction:    stats.direction,
+		Reversed:     stats.reversed,
+		AskedAt:      stats.askedAt,
+	}
+	if err := b.sessionService.SaveSession(&session); err != nil {
+		fmt.Printf("Error saving session for user %d: %v\n", userID, err)
+	}
+}
+
+// restoreSessions loads the sessions saved before the last shutdown.
+func (b *Bot) restoreSessions() error {
+	sessions, err := b.sessionService.GetSessions()
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.State != "" {
+			b.userStates[session.TelegramID] = session.State
+		}
+		if !strings.HasPrefix(session.State, "training_") {
+			continue
+		}
+
+		stats := trainingStats{
+			correct:      session.Correct,
+			incorrect:    session.Incorrect,
+			currentIndex: session.CurrentIndex,
+			direction:    session.Direction,
+			reversed:     session.Reversed,
+			askedAt:      session.AskedAt,
+		}
+		for _, id := range strings.Split(session.WordIDs, ",") {
+			if wordID, err := strconv.ParseUint(id, 10, 32); err == nil {
+				stats.words = append(stats.words, uint(wordID))
+			}
+		}
+		b.trainingStats[session.TelegramID] = stats
+		b.trainingWords[session.TelegramID] = session.WordID
+	}
+
+	fmt.Printf("Restored %d session(s)\n", len(sessions))
+	return nil
+}
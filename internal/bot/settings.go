@@ -0,0 +1,37 @@
+package bot
+
+import (
+	"fmt"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func (b *Bot) handleSettings(c tele.Context) error {
+	user, err := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	if err != nil {
+		return c.Send("Error creating user profile")
+	}
+
+	b.userStates[c.Sender().ID] = "waiting_for_session_length"
+	return c.Send(fmt.Sprintf("⚙️ Settings\n\nDefault session length: %d words\n\n"+
+		"Send a number from 1 to %d to change it.", user.SessionLength, maxSessionLength),
+		b.getLengthMenu(false))
+}
+
+func (b *Bot) handleSessionLengthSetting(c tele.Context, text string) error {
+	length, err := parseSessionLength(text)
+	if err != nil {
+		return c.Send(err.Error(), b.getLengthMenu(false))
+	}
+
+	user, err := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	if err != nil {
+		return c.Send("Error creating user profile")
+	}
+	if err := b.userService.SetSessionLength(user.ID, length); err != nil {
+		return c.Send("Error saving settings")
+	}
+
+	delete(b.userStates, c.Sender().ID)
+	return c.Send(fmt.Sprintf("Default session length set to %d words", length), b.getMainMenu())
+}
@@ -0,0 +1,49 @@
+package bot
+
+import (
+	"english-words-bot/internal/services"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func (b *Bot) handleStats(c tele.Context) error {
+	user, err := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	if err != nil {
+		return c.Send("Error creating user profile")
+	}
+
+	stats, err := b.statsService.GetUserStats(user.ID, time.Now())
+	if err != nil {
+		fmt.Printf("Error getting stats for user %d: %v\n", user.ID, err)
+		return c.Send("Error getting statistics")
+	}
+
+	var response strings.Builder
+	response.WriteString("📊 Your statistics\n\n")
+	response.WriteString(fmt.Sprintf("Words: %d (mastered: %d)\n", stats.TotalWords, stats.MasteredWords))
+	response.WriteString(fmt.Sprintf("Streak: %d day(s) (best: %d)\n\n", stats.CurrentStreak, stats.BestStreak))
+	writePeriod(&response, "Last 7 days", stats.Last7Days)
+	writePeriod(&response, "Last 30 days", stats.Last30Days)
+	writePeriod(&response, "All time", stats.Lifetime)
+
+	if len(stats.HardestWords) > 0 {
+		response.WriteString("\nHardest words:\n")
+		for i, word := range stats.HardestWords {
+			response.WriteString(fmt.Sprintf("%d. %s - %s (missed %d of %d)\n",
+				i+1, html.EscapeString(word.EnglishWord), html.EscapeString(word.Translation), word.Mistakes, word.Reviews))
+		}
+	}
+
+	return c.Send(response.String(), &tele.SendOptions{
+		ParseMode: tele.ModeHTML,
+	})
+}
+
+func writePeriod(response *strings.Builder, title string, p services.PeriodStats) {
+	response.WriteString(fmt.Sprintf("%s:\nWords added: %d\nReviews: %d\nAccuracy: %.1f%%\n\n",
+		title, p.WordsAdded, p.Reviews, p.Accuracy()))
+}
@@ -0,0 +1,440 @@
+package bot
+
+import (
+	"english-words-bot/internal/answer"
+	"english-words-bot/internal/models"
+	"english-words-bot/internal/services"
+	"english-words-bot/internal/srs"
+	"errors"
+	"fmt"
+	"html"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+const (
+	modeNWords     = "n_words"
+	modeContinuous = "continuous"
+	modeDue        = "due"
+	modeLeitner    = "leitner"
+	modeQuiz       = "quiz"
+)
+
+const (
+	directionForward = "forward" // English → translation
+	directionReverse = "reverse" // translation → English
+	directionMixed   = "mixed"   // random per question
+)
+
+const (
+	defaultSessionLength = 10
+	// maxSessionLength caps the number of words in a fixed-size session.
+	maxSessionLength = 200
+)
+
+// askLength asks how many words the fixed-size session should have.
+func (b *Bot) askLength(c tele.Context) error {
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	b.userStates[c.Sender().ID] = "choosing_length"
+	return c.Send(fmt.Sprintf("How many words? Choose or type a number (your default is %d).", user.SessionLength),
+		b.getLengthMenu(true))
+}
+
+func (b *Bot) handleLengthChoice(c tele.Context, text string) error {
+	if text == "⭐ Default" {
+		return b.askDirection(c, modeNWords)
+	}
+
+	length, err := parseSessionLength(text)
+	if err != nil {
+		return c.Send(err.Error(), b.getLengthMenu(true))
+	}
+	return b.askDirection(c, fmt.Sprintf("%s:%d", modeNWords, length))
+}
+
+func parseSessionLength(text string) (int, error) {
+	length, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || length < 1 || length > maxSessionLength {
+		return 0, fmt.Errorf("Please enter a number from 1 to %d", maxSessionLength)
+	}
+	return length, nil
+}
+
+// askDirection remembers the chosen mode and asks which way to translate.
+// The mode may carry a session length as "n_words:20".
+func (b *Bot) askDirection(c tele.Context, mode string) error {
+	b.userStates[c.Sender().ID] = "choosing_direction_" + mode
+	return c.Send("Choose translation direction:", b.getDirectionMenu())
+}
+
+func (b *Bot) handleDirection(c tele.Context, direction string) error {
+	state := b.userStates[c.Sender().ID]
+	if !strings.HasPrefix(state, "choosing_direction_") {
+		return c.Send("Choose training mode:", b.getTrainingMenu())
+	}
+
+	mode, lengthText, _ := strings.Cut(strings.TrimPrefix(state, "choosing_direction_"), ":")
+	length, _ := strconv.Atoi(lengthText)
+	return b.sendTrainingStart(c, mode, direction, length)
+}
+
+func pickReversed(direction string) bool {
+	switch direction {
+	case directionReverse:
+		return true
+	case directionMixed:
+		return rand.Intn(2) == 0
+	default:
+		return false
+	}
+}
+
+// question returns the side of the word shown to the user and the side
+// expected as the answer. The part of speech is shown in both directions to
+// tell homographs apart.
+func question(word *models.Word, reversed bool) (prompt, answer string) {
+	if reversed {
+		prompt = word.Translation
+		if word.PartOfSpeech != "" {
+			prompt += " (" + word.PartOfSpeech + ")"
+		}
+		return prompt, word.EnglishWord
+	}
+	return word.Headword().String(), word.Translation
+}
+
+// escapeJoin lists answers in an HTML message.
+func escapeJoin(answers []string) string {
+	escaped := make([]string, len(answers))
+	for i, answer := range answers {
+		escaped[i] = html.EscapeString(answer)
+	}
+	return strings.Join(escaped, ", ")
+}
+
+// acceptedAnswers lists every answer that counts as correct for the word.
+func acceptedAnswers(word *models.Word, reversed bool) []string {
+	if reversed {
+		return []string{word.EnglishWord}
+	}
+	return word.Translations()
+}
+
+// startTraining prepares a session in the given mode. A zero length means
+// the user's default session length.
+func (b *Bot) startTraining(userID int64, mode, direction string, length int) error {
+	user, _ := b.userService.GetOrCreateUser(userID, "")
+	if length <= 0 {
+		length = user.SessionLength
+	}
+	if length <= 0 {
+		length = defaultSessionLength
+	}
+
+	var words []models.Word
+	var err error
+	if mode == modeDue || mode == modeLeitner {
+		if mode == modeDue {
+			words, err = b.wordService.GetDueWords(user.ID, time.Now())
+		} else {
+			words, err = b.wordService.GetLeitnerDueWords(user.ID, time.Now())
+		}
+		if err != nil {
+			return err
+		}
+		if len(words) == 0 {
+			return fmt.Errorf("no words are due for review right now")
+		}
+	} else {
+		words, err = b.wordService.GetTrainingWords(user.ID)
+		if err != nil {
+			return err
+		}
+		if len(words) == 0 {
+			return fmt.Errorf("no words available for training")
+		}
+		if mode == modeQuiz && len(words) < 2 {
+			return fmt.Errorf("add at least 2 words to play the quiz")
+		}
+	}
+
+	// Очищаємо попередні результати
+	delete(b.trainingWords, userID)
+	delete(b.trainingStats, userID)
+
+	// Створюємо нову структуру для статистики
+	stats := trainingStats{
+		correct:      0,
+		incorrect:    0,
+		words:        make([]uint, 0),
+		currentIndex: 0,
+		direction:    direction,
+		reversed:     pickReversed(direction),
+		askedAt:      time.Now(),
+	}
+
+	switch mode {
+	case modeNWords, modeQuiz:
+		// Перемішуємо слова
+		rand.Shuffle(len(words), func(i, j int) {
+			words[i], words[j] = words[j], words[i]
+		})
+		// Беремо перші N слів (або менше, якщо слів менше N)
+		count := length
+		if len(words) < count {
+			count = len(words)
+		}
+		for i := 0; i < count; i++ {
+			stats.words = append(stats.words, words[i].ID)
+		}
+	case modeDue, modeLeitner:
+		// Слова вже відсортовані в порядку повторення
+		for _, word := range words {
+			stats.words = append(stats.words, word.ID)
+		}
+	default:
+		// Для режиму безлімітного тренування
+		stats.words = []uint{words[rand.Intn(len(words))].ID}
+	}
+	fmt.Printf("Selected words for training: %v\n", stats.words)
+
+	// Зберігаємо статистику
+	b.trainingStats[userID] = stats
+	b.trainingWords[userID] = stats.words[0]
+	b.userStates[userID] = "training_" + mode
+
+	return nil
+}
+
+// sendTrainingStart starts a session in the given mode and asks the first word.
+func (b *Bot) sendTrainingStart(c tele.Context, mode, direction string, length int) error {
+	err := b.startTraining(c.Sender().ID, mode, direction, length)
+	if err != nil {
+		delete(b.userStates, c.Sender().ID)
+		return c.Send(err.Error(), b.getTrainingMenu())
+	}
+
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	word, err := b.wordService.GetWordByID(user.ID, b.trainingWords[c.Sender().ID])
+	if err != nil {
+		fmt.Printf("Error getting first word: %v\n", err)
+		return c.Send("Error getting word for training")
+	}
+
+	stats := b.trainingStats[c.Sender().ID]
+	header := ""
+	if mode == modeDue || mode == modeLeitner {
+		header = fmt.Sprintf("%d word(s) are due for review.", len(stats.words))
+	}
+	if mode == modeQuiz {
+		// Прибираємо клавіатуру меню, відповіді даються кнопками під питанням
+		err := c.Send("🧩 Quiz started! Tap the correct answer.\nType /stop to end training", tele.RemoveKeyboard)
+		if err != nil {
+			return err
+		}
+	}
+	return b.sendQuestion(c, mode, word, stats, header)
+}
+
+// sendQuestion asks the given word, optionally preceded by a header such as
+// the feedback on the previous answer.
+func (b *Bot) sendQuestion(c tele.Context, mode string, word *models.Word, stats trainingStats, header string) error {
+	prompt, _ := question(word, stats.reversed)
+	response := fmt.Sprintf("Translate this word: %s", html.EscapeString(prompt))
+	if header != "" {
+		response = header + "\n\n" + response
+	}
+
+	if mode == modeQuiz {
+		markup, err := b.getQuizMarkup(word, stats.reversed)
+		if err != nil {
+			fmt.Printf("Error building quiz options for word %d: %v\n", word.ID, err)
+			return c.Send("Error getting answer options")
+		}
+		return c.Send(response, markup)
+	}
+
+	return c.Send(response+"\nType /stop to end training", tele.RemoveKeyboard)
+}
+
+// handleTrainingAnswer checks a typed answer for the current word and moves
+// the session on to the next word.
+func (b *Bot) handleTrainingAnswer(c tele.Context, mode, text string) error {
+	userID := c.Sender().ID
+	wordID, exists := b.trainingWords[userID]
+	if !exists {
+		fmt.Printf("No word ID found for user %d\n", userID)
+		return c.Send("Something went wrong. Please start training again.")
+	}
+
+	if mode == modeQuiz {
+		return c.Send("Please tap one of the answer buttons or type /stop to end training")
+	}
+
+	stats, ok := b.trainingStats[userID]
+	if !ok {
+		fmt.Printf("No training stats found for user %d\n", userID)
+		return c.Send("Something went wrong. Please start training again.")
+	}
+
+	user, _ := b.userService.GetOrCreateUser(userID, c.Sender().Username)
+	word, err := b.wordService.GetWordByID(user.ID, wordID)
+	if errors.Is(err, services.ErrWordNotFound) {
+		return b.advanceTraining(c, mode, stats, deletedWordNote)
+	}
+	if err != nil {
+		fmt.Printf("Error getting word for training: %v\n", err)
+		return c.Send("Error getting word for training")
+	}
+
+	fmt.Printf("Current training state - Mode: %s, Index: %d, Words: %v, Current Word ID: %d\n",
+		mode, stats.currentIndex, stats.words, wordID)
+
+	_, expected := question(word, stats.reversed)
+	accepted := acceptedAnswers(word, stats.reversed)
+
+	var feedback string
+	quality := srs.QualityWrong
+	switch answer.Check(text, accepted...) {
+	case answer.Exact:
+		stats.correct++
+		quality = srs.QualityPerfect
+		feedback = "Correct! 🎉"
+		if len(accepted) > 1 {
+			feedback += fmt.Sprintf("\nAll translations: %s", escapeJoin(accepted))
+		}
+	case answer.NearMiss:
+		stats.correct++
+		quality = srs.QualityHard
+		feedback = fmt.Sprintf("Correct! 🎉 Watch your spelling: %s", escapeJoin(accepted))
+	default:
+		stats.incorrect++
+		fmt.Printf("Incorrect answer for word %s. Expected: %s, Got: %s\n",
+			word.EnglishWord, expected, text)
+		switch {
+		case stats.reversed:
+			feedback = fmt.Sprintf("Incorrect. The correct English word is: %s", html.EscapeString(expected))
+		case len(accepted) > 1:
+			feedback = fmt.Sprintf("Incorrect. Accepted translations: %s", escapeJoin(accepted))
+		default:
+			feedback = fmt.Sprintf("Incorrect. The correct translation is: %s", html.EscapeString(expected))
+		}
+		feedback += exampleHint(word)
+	}
+
+	feedback += b.recordAnswer(mode, word, stats, text, quality)
+	return b.advanceTraining(c, mode, stats, feedback)
+}
+
+// recordAnswer logs an answer, updates the learning state of the word and
+// returns a note to append to the feedback, if any.
+func (b *Bot) recordAnswer(mode string, word *models.Word, stats trainingStats, given string, quality int) string {
+	direction := directionForward
+	if stats.reversed {
+		direction = directionReverse
+	}
+	err := b.reviewService.LogReview(&models.ReviewLog{
+		UserID:    word.UserID,
+		WordID:    word.ID,
+		Direction: direction,
+		Mode:      mode,
+		Answer:    given,
+		Correct:   quality >= srs.QualityHard,
+		LatencyMs: time.Since(stats.askedAt).Milliseconds(),
+	})
+	if err != nil {
+		fmt.Printf("Error logging review for word %d: %v\n", word.ID, err)
+	}
+
+	if err := b.wordService.RecordReview(word.UserID, word.ID, quality); err != nil {
+		fmt.Printf("Error recording review for word %d: %v\n", word.ID, err)
+	}
+
+	if mode == modeLeitner {
+		box, err := b.wordService.RecordLeitnerReview(word.UserID, word.ID, quality >= srs.QualityHard)
+		if err != nil {
+			fmt.Printf("Error moving word %d between Leitner boxes: %v\n", word.ID, err)
+			return ""
+		}
+		return fmt.Sprintf("\n📦 Box %d/%d", box, srs.LeitnerBoxes)
+	}
+
+	return ""
+}
+
+// advanceTraining stores the updated stats and asks the next word, or shows
+// the results when the session is over.
+func (b *Bot) advanceTraining(c tele.Context, mode string, stats trainingStats, feedback string) error {
+	userID := c.Sender().ID
+	nextWord, err := b.nextTrainingWord(userID, mode, &stats)
+	if err != nil {
+		fmt.Printf("Error getting next word: %v\n", err)
+		return c.Send("Error getting next word")
+	}
+	if nextWord == nil {
+		// Тренування завершено
+		b.clearTraining(userID)
+		response := "Training completed!\n" + formatResults(stats)
+		if feedback != "" {
+			response = feedback + "\n\n" + response
+		}
+		return c.Send(response, b.getMainMenu(), &tele.SendOptions{
+			ParseMode: tele.ModeHTML,
+		})
+	}
+
+	fmt.Printf("Next word set: ID=%d, Word=%s\n", nextWord.ID, nextWord.EnglishWord)
+	stats.reversed = pickReversed(stats.direction)
+	stats.askedAt = time.Now()
+	b.trainingWords[userID] = nextWord.ID
+	b.trainingStats[userID] = stats // Оновлюємо статистику
+	return b.sendQuestion(c, mode, nextWord, stats, feedback)
+}
+
+// deletedWordNote replaces the feedback when the asked word was deleted
+// while the session was running.
+const deletedWordNote = "This word was deleted, skipping it."
+
+// nextTrainingWord advances the session and returns the next word to ask, or
+// nil when the session is over. Words deleted during the session are
+// skipped.
+func (b *Bot) nextTrainingWord(userID int64, mode string, stats *trainingStats) (*models.Word, error) {
+	user, _ := b.userService.GetOrCreateUser(userID, "")
+	if mode == modeContinuous {
+		// Для безлімітного режиму беремо нове випадкове слово
+		word, err := b.wordService.GetRandomWord(user.ID)
+		if errors.Is(err, services.ErrWordNotFound) {
+			return nil, nil // слів для тренування не залишилось
+		}
+		return word, err
+	}
+
+	for stats.currentIndex++; stats.currentIndex < len(stats.words); stats.currentIndex++ {
+		word, err := b.wordService.GetWordByID(user.ID, stats.words[stats.currentIndex])
+		if errors.Is(err, services.ErrWordNotFound) {
+			continue
+		}
+		return word, err
+	}
+	return nil, nil
+}
+
+func (b *Bot) clearTraining(userID int64) {
+	delete(b.userStates, userID)
+	delete(b.trainingWords, userID)
+	delete(b.trainingStats, userID)
+}
+
+func formatResults(stats trainingStats) string {
+	total := stats.correct + stats.incorrect
+	accuracy := 0.0
+	if total > 0 {
+		accuracy = float64(stats.correct) / float64(total) * 100
+	}
+	return fmt.Sprintf("Results:\nCorrect: %d\nIncorrect: %d\nAccuracy: %.1f%%",
+		stats.correct, stats.incorrect, accuracy)
+}
@@ -0,0 +1,210 @@
+package bot
+
+import (
+	"english-words-bot/internal/models"
+	"english-words-bot/internal/services"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// trashPurgeInterval is how often words past the retention period are
+// removed from the trash.
+const trashPurgeInterval = time.Hour
+
+// purgeTrash periodically deletes for good the words that have been in the
+// trash longer than the retention period.
+func (b *Bot) purgeTrash() {
+	for {
+		purged, err := b.wordService.PurgeDeletedWords(time.Now().Add(-b.trashRetention))
+		if err != nil {
+			fmt.Printf("Error purging trash: %v\n", err)
+		} else if purged > 0 {
+			fmt.Printf("Purged %d word(s) from trash\n", purged)
+		}
+		time.Sleep(trashPurgeInterval)
+	}
+}
+
+// renderTrashPage builds one page of the user's deleted words with restore
+// and delete-forever buttons.
+func (b *Bot) renderTrashPage(userID uint, page int) (string, *tele.ReplyMarkup, error) {
+	words, total, err := b.wordService.GetDeletedWordsPage(userID, page, wordsPageSize)
+	if err != nil {
+		return "", nil, err
+	}
+
+	pages := int((total + wordsPageSize - 1) / wordsPageSize)
+	if page >= pages && pages > 0 {
+		page = pages - 1
+		words, total, err = b.wordService.GetDeletedWordsPage(userID, page, wordsPageSize)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	if total == 0 {
+		return "Your trash is empty.", nil, nil
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("Deleted words (removed forever after %d days):\n\n", int(b.trashRetention.Hours()/24)))
+
+	menu := &tele.ReplyMarkup{}
+	rows := make([]tele.Row, 0, len(words)+1)
+	for i, word := range words {
+		num := page*wordsPageSize + i + 1
+		response.WriteString(fmt.Sprintf("%d. %s - %s (deleted %s)\n",
+			num, html.EscapeString(word.Headword().String()), html.EscapeString(word.Translation), word.DeletedAt.Time.Format("2006-01-02")))
+
+		id := strconv.FormatUint(uint64(word.ID), 10)
+		rows = append(rows, menu.Row(
+			menu.Data(fmt.Sprintf("♻️ %d. %s", num, word.EnglishWord), "trash_restore", id, strconv.Itoa(page)),
+			menu.Data("❌", "trash_purge", id, strconv.Itoa(page)),
+		))
+	}
+	response.WriteString(fmt.Sprintf("\nPage %d of %d (%d words)", page+1, pages, total))
+
+	var nav tele.Row
+	if page > 0 {
+		nav = append(nav, menu.Data("◀️", "trash_page", strconv.Itoa(page-1)))
+	}
+	if page < pages-1 {
+		nav = append(nav, menu.Data("▶️", "trash_page", strconv.Itoa(page+1)))
+	}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+	menu.Inline(rows...)
+
+	return response.String(), menu, nil
+}
+
+func (b *Bot) handleTrash(c tele.Context) error {
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	text, markup, err := b.renderTrashPage(user.ID, 0)
+	if err != nil {
+		return c.Send("Error getting deleted words")
+	}
+	if markup == nil {
+		return c.Send(text)
+	}
+	return c.Send(text, markup)
+}
+
+// showTrashPage replaces the message of a callback with a page of the trash.
+func (b *Bot) showTrashPage(c tele.Context, page int) error {
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	text, markup, err := b.renderTrashPage(user.ID, page)
+	if err != nil {
+		return err
+	}
+
+	if markup == nil {
+		err = c.Edit(text)
+	} else {
+		err = c.Edit(text, markup)
+	}
+	if err != nil {
+		fmt.Printf("Error editing trash: %v\n", err)
+	}
+	return nil
+}
+
+func (b *Bot) handleTrashPage(c tele.Context) error {
+	page, _ := strconv.Atoi(c.Data())
+	if err := b.showTrashPage(c, page); err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Error getting deleted words"})
+	}
+	return c.Respond()
+}
+
+// trashCallback reads the word ID and page from trash callback data.
+func trashCallback(c tele.Context) (uint, int) {
+	idText, pageText, _ := strings.Cut(c.Data(), "|")
+	wordID, _ := strconv.ParseUint(idText, 10, 32)
+	page, _ := strconv.Atoi(pageText)
+	return uint(wordID), page
+}
+
+func (b *Bot) handleTrashRestore(c tele.Context) error {
+	wordID, page := trashCallback(c)
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+
+	word, err := b.wordService.RestoreWord(user.ID, wordID)
+	if errors.Is(err, services.ErrWordExists) {
+		return c.Respond(&tele.CallbackResponse{Text: fmt.Sprintf("\"%s\" is already in your dictionary", word.EnglishWord)})
+	}
+	if err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Word not found in trash"})
+	}
+
+	if err := b.showTrashPage(c, page); err != nil {
+		fmt.Printf("Error getting deleted words: %v\n", err)
+	}
+	return c.Respond(&tele.CallbackResponse{Text: fmt.Sprintf("\"%s\" restored", word.EnglishWord)})
+}
+
+// handleTrashPurge asks to confirm deleting a word forever.
+func (b *Bot) handleTrashPurge(c tele.Context) error {
+	wordID, page := trashCallback(c)
+
+	id := strconv.FormatUint(uint64(wordID), 10)
+	menu := &tele.ReplyMarkup{}
+	menu.Inline(menu.Row(
+		menu.Data("✅ Yes, delete forever", "trash_purge_yes", id, strconv.Itoa(page)),
+		menu.Data("❌ Cancel", "trash_page", strconv.Itoa(page)),
+	))
+
+	if err := c.Edit("Delete this word forever? This cannot be undone.", menu); err != nil {
+		fmt.Printf("Error editing trash: %v\n", err)
+	}
+	return c.Respond()
+}
+
+func (b *Bot) handleTrashPurgeConfirm(c tele.Context) error {
+	wordID, page := trashCallback(c)
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+
+	if err := b.wordService.PurgeWord(user.ID, wordID); err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Word not found in trash"})
+	}
+
+	if err := b.showTrashPage(c, page); err != nil {
+		fmt.Printf("Error getting deleted words: %v\n", err)
+	}
+	return c.Respond(&tele.CallbackResponse{Text: "Word deleted forever"})
+}
+
+// undoButton offers to restore a word that was just deleted from a list.
+func undoButton(menu *tele.ReplyMarkup, word *models.Word, kind string, page int) tele.Btn {
+	id := strconv.FormatUint(uint64(word.ID), 10)
+	return menu.Data("↩️ Undo delete: "+word.EnglishWord, "word_undo", id, kind, strconv.Itoa(page))
+}
+
+// handleWordUndo restores a word deleted from a list and shows the list
+// again without the undo button.
+func (b *Bot) handleWordUndo(c tele.Context) error {
+	fields := strings.Split(c.Data(), "|")
+	wordID, _ := strconv.ParseUint(fields[0], 10, 32)
+	kind, page := listPosition(fields[1:])
+
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	word, err := b.wordService.RestoreWord(user.ID, uint(wordID))
+	if errors.Is(err, services.ErrWordExists) {
+		return c.Respond(&tele.CallbackResponse{Text: fmt.Sprintf("\"%s\" is already in your dictionary", word.EnglishWord)})
+	}
+	if err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Word not found in trash"})
+	}
+
+	if err := b.showWordsPage(c, kind, page, nil); err != nil {
+		fmt.Printf("Error getting words: %v\n", err)
+	}
+	return c.Respond(&tele.CallbackResponse{Text: fmt.Sprintf("\"%s\" restored", word.EnglishWord)})
+}
@@ -0,0 +1,160 @@
+package bot
+
+import (
+	"english-words-bot/internal/models"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// sortNames are the button labels for the word list sort orders, in the
+// order they are offered.
+var sortNames = []struct{ sort, name string }{
+	{models.SortOldest, "Oldest first"},
+	{models.SortNewest, "Newest first"},
+	{models.SortAlphabetical, "A-Z"},
+	{models.SortMostMissed, "Most missed"},
+	{models.SortLeastReviewed, "Least recently reviewed"},
+}
+
+// addedDaysChoices are the offered "added in the last N days" filters.
+var addedDaysChoices = []int{0, 1, 7, 30, 90}
+
+// describeView sums up the sorting and filters of the user's word list.
+func (b *Bot) describeView(user *models.User) string {
+	view := user.WordList
+	var parts []string
+	for _, choice := range sortNames {
+		if choice.sort == view.Sort || (view.Sort == "" && choice.sort == models.SortOldest) {
+			parts = append(parts, strings.ToLower(choice.name))
+		}
+	}
+	if view.DeckID != 0 {
+		if deck, err := b.deckService.GetDeckByID(user.ID, view.DeckID); err == nil {
+			parts = append(parts, "deck "+html.EscapeString(deck.Name))
+		}
+	}
+	if view.AddedDays > 0 {
+		parts = append(parts, fmt.Sprintf("added in the last %s", daysName(view.AddedDays)))
+	}
+	if view.NeverTrained {
+		parts = append(parts, "never trained")
+	}
+	return strings.Join(parts, " · ")
+}
+
+func daysName(days int) string {
+	if days == 1 {
+		return "day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+// renderViewChoice builds the sort and filter settings of the word list with
+// the current choices marked.
+func (b *Bot) renderViewChoice(user *models.User) (string, *tele.ReplyMarkup) {
+	view := user.WordList
+	menu := &tele.ReplyMarkup{}
+	label := func(selected bool, name string) string {
+		if selected {
+			return "✅ " + name
+		}
+		return name
+	}
+
+	var rows []tele.Row
+	var row tele.Row
+	for _, choice := range sortNames {
+		selected := choice.sort == view.Sort || (view.Sort == "" && choice.sort == models.SortOldest)
+		row = append(row, menu.Data(label(selected, choice.name), "words_view", "sort", choice.sort))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+		row = nil
+	}
+
+	decks, err := b.deckService.GetUserDecks(user.ID)
+	if err != nil {
+		fmt.Printf("Error getting decks: %v\n", err)
+	}
+	if len(decks) > 0 {
+		row = tele.Row{menu.Data(label(view.DeckID == 0, "All decks"), "words_view", "deck", "0")}
+		for _, deck := range decks {
+			id := strconv.FormatUint(uint64(deck.ID), 10)
+			row = append(row, menu.Data(label(view.DeckID == deck.ID, "🗂 "+deck.Name), "words_view", "deck", id))
+			if len(row) == 3 {
+				rows = append(rows, row)
+				row = nil
+			}
+		}
+		if len(row) > 0 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+
+	for _, days := range addedDaysChoices {
+		name := "Any time"
+		if days > 0 {
+			name = fmt.Sprintf("%dd", days)
+		}
+		row = append(row, menu.Data(label(view.AddedDays == days, name), "words_view", "added", strconv.Itoa(days)))
+	}
+	rows = append(rows, row)
+
+	rows = append(rows,
+		menu.Row(menu.Data(label(view.NeverTrained, "Never trained only"), "words_view", "untrained")),
+		menu.Row(menu.Data("📚 Show words", "words_page", listWords, "0")),
+	)
+	menu.Inline(rows...)
+
+	return "Sort and filter your words.\nCurrent view: " + b.describeView(user), menu
+}
+
+// handleWordsView opens the sort and filter settings of the word list or
+// changes one of them.
+func (b *Bot) handleWordsView(c tele.Context) error {
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	view := user.WordList
+
+	key, value, _ := strings.Cut(c.Data(), "|")
+	switch key {
+	case "sort":
+		view.Sort = value
+		if view.Sort == models.SortOldest {
+			view.Sort = ""
+		}
+	case "deck":
+		deckID, _ := strconv.ParseUint(value, 10, 32)
+		view.DeckID = uint(deckID)
+		if view.DeckID != 0 {
+			if _, err := b.deckService.GetDeckByID(user.ID, view.DeckID); err != nil {
+				return c.Respond(&tele.CallbackResponse{Text: "Deck not found"})
+			}
+		}
+	case "added":
+		view.AddedDays, _ = strconv.Atoi(value)
+	case "untrained":
+		view.NeverTrained = !view.NeverTrained
+	}
+
+	if view != user.WordList {
+		if err := b.userService.SetWordListView(user.ID, view); err != nil {
+			return c.Respond(&tele.CallbackResponse{Text: "Error saving the view"})
+		}
+		user.WordList = view
+	}
+
+	text, markup := b.renderViewChoice(user)
+	if err := c.Edit(text, markup); err != nil {
+		fmt.Printf("Error editing word list view: %v\n", err)
+	}
+	return c.Respond()
+}
@@ -0,0 +1,276 @@
+package bot
+
+import (
+	"english-words-bot/internal/models"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// wordsPageSize keeps a page of the dictionary, with a row of buttons per
+// word, well below Telegram's message and keyboard limits.
+const wordsPageSize = 10
+
+// Listing kinds: the same paginated list is used to browse, edit, delete
+// and show search results.
+const (
+	listWords  = "list"
+	listEdit   = "edit"
+	listDelete = "delete"
+	listSearch = "search"
+)
+
+// renderWordsPage builds one page of the user's dictionary, sorted and
+// filtered as the user chose, or of the words matching the query for search
+// results, with per-word buttons and navigation. Pages are counted from zero;
+// a page past the end is clamped to the last one.
+func (b *Bot) renderWordsPage(user *models.User, kind, query string, page int) (string, *tele.ReplyMarkup, error) {
+	fetch := func(page int) ([]models.Word, int64, error) {
+		if kind == listSearch {
+			return b.wordService.SearchWords(user.ID, query, page, wordsPageSize)
+		}
+		return b.wordService.GetUserWordsPage(user.ID, user.WordList, page, wordsPageSize)
+	}
+	filtered := kind != listSearch && user.WordList != (models.WordListView{})
+
+	words, total, err := fetch(page)
+	if err != nil {
+		return "", nil, err
+	}
+
+	pages := int((total + wordsPageSize - 1) / wordsPageSize)
+	if page >= pages && pages > 0 {
+		page = pages - 1
+		words, total, err = fetch(page)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	if total == 0 {
+		switch {
+		case filtered:
+			menu := &tele.ReplyMarkup{}
+			menu.Inline(menu.Row(menu.Data("⚙️ Sort & filter", "words_view", "open")))
+			return fmt.Sprintf("No words match the current view (%s).", b.describeView(user)), menu, nil
+		case kind == listSearch:
+			return fmt.Sprintf("Nothing found for \"%s\"", html.EscapeString(query)), nil, nil
+		case kind == listEdit:
+			return "You don't have any words to edit. Add some first!", nil, nil
+		case kind == listDelete:
+			return "You don't have any words to delete. Add some first!", nil, nil
+		default:
+			return "You don't have any words yet. Add some!", nil, nil
+		}
+	}
+
+	var response strings.Builder
+	switch kind {
+	case listEdit:
+		response.WriteString("Tap ✏️ next to the word you want to edit:\n\n")
+	case listDelete:
+		response.WriteString("Tap 🗑 next to the word you want to delete:\n\n")
+	case listSearch:
+		response.WriteString(fmt.Sprintf("Search results for \"%s\":\n\n", html.EscapeString(query)))
+	default:
+		if filtered {
+			response.WriteString(fmt.Sprintf("Your words (%s):\n\n", b.describeView(user)))
+		} else {
+			response.WriteString("Your words:\n\n")
+		}
+	}
+
+	menu := &tele.ReplyMarkup{}
+	rows := make([]tele.Row, 0, len(words)+1)
+	for i, word := range words {
+		num := page*wordsPageSize + i + 1
+		response.WriteString(fmt.Sprintf("%d. %s - %s\n", num,
+			html.EscapeString(word.Headword().String()), html.EscapeString(word.Translation)))
+		rows = append(rows, wordRow(menu, &word, num, kind, page))
+	}
+	response.WriteString(fmt.Sprintf("\nPage %d of %d (%d words)", page+1, pages, total))
+
+	if nav := pageRow(menu, kind, page, pages); len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+	if kind == listWords {
+		rows = append(rows, menu.Row(menu.Data("⚙️ Sort & filter", "words_view", "open")))
+	}
+	menu.Inline(rows...)
+
+	return response.String(), menu, nil
+}
+
+// wordRow builds the ✏️/🗑 buttons for one listed word. The buttons carry the
+// word ID, so they keep pointing to the same word when the list changes.
+func wordRow(menu *tele.ReplyMarkup, word *models.Word, num int, kind string, page int) tele.Row {
+	id := strconv.FormatUint(uint64(word.ID), 10)
+	label := fmt.Sprintf("%d. %s", num, word.EnglishWord)
+	edit := menu.Data("✏️ "+label, "word_edit", id)
+	remove := menu.Data("🗑 "+label, "word_delete", id, kind, strconv.Itoa(page))
+
+	switch kind {
+	case listEdit:
+		return menu.Row(edit)
+	case listDelete:
+		return menu.Row(remove)
+	default: // listWords, listSearch
+		edit.Text = "✏️"
+		remove.Text = "🗑"
+		return menu.Row(menu.Data(label, "word_card", id), edit, remove)
+	}
+}
+
+// pageRow builds the ◀️/▶️ buttons that switch the list to another page.
+func pageRow(menu *tele.ReplyMarkup, kind string, page, pages int) tele.Row {
+	var nav tele.Row
+	if page > 0 {
+		nav = append(nav, menu.Data("◀️", "words_page", kind, strconv.Itoa(page-1)))
+	}
+	if page < pages-1 {
+		nav = append(nav, menu.Data("▶️", "words_page", kind, strconv.Itoa(page+1)))
+	}
+	return nav
+}
+
+// sendWordsPage sends the first page of a word list.
+func (b *Bot) sendWordsPage(c tele.Context, kind string) error {
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	text, markup, err := b.renderWordsPage(user, kind, b.searchQueries[c.Sender().ID], 0)
+	if err != nil {
+		return c.Send("Error getting words")
+	}
+	if markup == nil {
+		return c.Send(text)
+	}
+	return c.Send(text, markup)
+}
+
+// showWordsPage replaces the message of a callback with a page of the list.
+// A just deleted word gets an undo button under the list.
+func (b *Bot) showWordsPage(c tele.Context, kind string, page int, deleted *models.Word) error {
+	query, ok := b.searchQueries[c.Sender().ID]
+	if kind == listSearch && !ok {
+		return c.Edit("This search has expired. Please search again.")
+	}
+
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	text, markup, err := b.renderWordsPage(user, kind, query, page)
+	if err != nil {
+		return err
+	}
+
+	if deleted != nil {
+		if markup == nil {
+			markup = &tele.ReplyMarkup{}
+		}
+		undo := undoButton(markup, deleted, kind, page)
+		markup.InlineKeyboard = append(markup.InlineKeyboard, []tele.InlineButton{*undo.Inline()})
+	}
+
+	if markup == nil {
+		err = c.Edit(text)
+	} else {
+		err = c.Edit(text, markup)
+	}
+	if err != nil {
+		fmt.Printf("Error editing word list: %v\n", err)
+	}
+	return nil
+}
+
+// handleWordsPage switches an already sent word list to another page.
+func (b *Bot) handleWordsPage(c tele.Context) error {
+	kind, pageText, _ := strings.Cut(c.Data(), "|")
+	page, _ := strconv.Atoi(pageText)
+
+	if err := b.showWordsPage(c, kind, page, nil); err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Error getting words"})
+	}
+	return c.Respond()
+}
+
+// getCallbackWord loads the sender's word whose ID is the first field of the
+// callback data.
+func (b *Bot) getCallbackWord(c tele.Context) (*models.Word, []string, error) {
+	fields := strings.Split(c.Data(), "|")
+	wordID, err := strconv.ParseUint(fields[0], 10, 32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, _ := b.userService.GetOrCreateUser(c.Sender().ID, c.Sender().Username)
+	word, err := b.wordService.GetWordByID(user.ID, uint(wordID))
+	if err != nil {
+		return nil, nil, err
+	}
+	return word, fields[1:], nil
+}
+
+// listPosition reads the list kind and page carried after the word ID.
+func listPosition(fields []string) (string, int) {
+	if len(fields) < 2 {
+		return listWords, 0
+	}
+	page, _ := strconv.Atoi(fields[1])
+	return fields[0], page
+}
+
+// handleWordEdit shows the card of a word with a button for each field. The
+// whole word can also be typed again as "english_word - translation".
+func (b *Bot) handleWordEdit(c tele.Context) error {
+	word, _, err := b.getCallbackWord(c)
+	if err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Word not found"})
+	}
+
+	b.userStates[c.Sender().ID] = fmt.Sprintf("waiting_for_word_edit_%d", word.ID)
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Error answering callback: %v\n", err)
+	}
+	card, markup := renderWordCard(word)
+	return c.Send(card+"\nTap a button to change one field, or send the whole word again: "+
+		"english_word - translation", markup)
+}
+
+// handleWordDelete asks to confirm deleting a word in place of the list.
+func (b *Bot) handleWordDelete(c tele.Context) error {
+	word, fields, err := b.getCallbackWord(c)
+	if err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Word not found"})
+	}
+	kind, page := listPosition(fields)
+
+	id := strconv.FormatUint(uint64(word.ID), 10)
+	menu := &tele.ReplyMarkup{}
+	menu.Inline(menu.Row(
+		menu.Data("✅ Yes, delete", "word_delete_yes", id, kind, strconv.Itoa(page)),
+		menu.Data("❌ Cancel", "words_page", kind, strconv.Itoa(page)),
+	))
+
+	if err := c.Edit(fmt.Sprintf("Delete \"%s - %s\"?",
+		html.EscapeString(word.Headword().String()), html.EscapeString(word.Translation)), menu); err != nil {
+		fmt.Printf("Error editing word list: %v\n", err)
+	}
+	return c.Respond()
+}
+
+func (b *Bot) handleWordDeleteConfirm(c tele.Context) error {
+	word, fields, err := b.getCallbackWord(c)
+	if err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Word not found"})
+	}
+	kind, page := listPosition(fields)
+
+	if err := b.wordService.DeleteWord(word.UserID, word.ID); err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Error deleting word"})
+	}
+
+	if err := b.showWordsPage(c, kind, page, word); err != nil {
+		fmt.Printf("Error getting words: %v\n", err)
+	}
+	return c.Respond(&tele.CallbackResponse{Text: "Word moved to trash"})
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"english-words-bot/internal/models"
+	"fmt"
 	"log"
 
 	"gorm.io/driver/sqlite"
@@ -11,15 +12,23 @@ import (
 var DB *gorm.DB
 
 func InitDB() {
+	if err := Open("words.db"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Open connects to the SQLite database at path and migrates the schema.
+func Open(path string) error {
 	var err error
-	DB, err = gorm.Open(sqlite.Open("words.db"), &gorm.Config{})
+	DB, err = gorm.Open(sqlite.Open(path), &gorm.Config{})
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	// Auto Migrate the schema
-	err = DB.AutoMigrate(&models.User{}, &models.Word{})
+	err = DB.AutoMigrate(&models.User{}, &models.Word{}, &models.Session{}, &models.ReviewLog{}, &models.Deck{})
 	if err != nil {
-		log.Fatal("Failed to migrate database:", err)
+		return fmt.Errorf("failed to migrate database: %w", err)
 	}
-} 
\ No newline at end of file
+	return nil
+}
@@ -0,0 +1,209 @@
+// Package exporter writes a user's dictionary in formats other tools can
+// read.
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"english-words-bot/internal/models"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Export formats.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatAnki = "anki"
+)
+
+// Formats lists the supported export formats.
+var Formats = []string{FormatCSV, FormatJSON, FormatAnki}
+
+// Source calls its argument for every word to export, stopping at the first
+// error.
+type Source func(func(*models.Word) error) error
+
+// FileName returns the name of the exported file for a format.
+func FileName(format string) string {
+	switch format {
+	case FormatJSON:
+		return "words.json"
+	case FormatAnki:
+		return "words-anki.txt"
+	default:
+		return "words.csv"
+	}
+}
+
+// Write writes the words of the source to w in the given format, one word at
+// a time.
+func Write(w io.Writer, format string, words Source) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, words)
+	case FormatJSON:
+		return writeJSON(w, words)
+	case FormatAnki:
+		return writeAnki(w, words)
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// csvHeader names the CSV columns; the first four are the ones the importer
+// reads back.
+var csvHeader = []string{
+	"english", "translation", "note", "deck", "examples",
+	"ease_factor", "interval", "repetitions", "due_at", "last_reviewed_at",
+	"leitner_box", "added_at",
+}
+
+func writeCSV(w io.Writer, words Source) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	err := words(func(word *models.Word) error {
+		return writer.Write([]string{
+			word.Headword().String(),
+			word.Translation,
+			word.Note,
+			strings.Join(deckNames(word), "; "),
+			strings.Join(word.ExampleList(), "\n"),
+			strconv.FormatFloat(word.EaseFactor, 'f', 2, 64),
+			strconv.Itoa(word.Interval),
+			strconv.Itoa(word.Repetitions),
+			formatTime(word.DueAt),
+			formatTime(word.LastReviewedAt),
+			strconv.Itoa(word.LeitnerBox),
+			word.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// jsonWord is the JSON form of a word.
+type jsonWord struct {
+	English       string       `json:"english"`
+	Transcription string       `json:"transcription,omitempty"`
+	PartOfSpeech  string       `json:"part_of_speech,omitempty"`
+	Translations  []string     `json:"translations"`
+	Examples      []string     `json:"examples,omitempty"`
+	Note          string       `json:"note,omitempty"`
+	Decks         []string     `json:"decks,omitempty"`
+	AddedAt       time.Time    `json:"added_at"`
+	Progress      jsonProgress `json:"progress"`
+}
+
+type jsonProgress struct {
+	EaseFactor     float64    `json:"ease_factor"`
+	Interval       int        `json:"interval"`
+	Repetitions    int        `json:"repetitions"`
+	DueAt          *time.Time `json:"due_at,omitempty"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty"`
+	LeitnerBox     int        `json:"leitner_box"`
+}
+
+// writeJSON writes a JSON array, one element per line.
+func writeJSON(w io.Writer, words Source) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	separator := "\n"
+	err := words(func(word *models.Word) error {
+		data, err := json.Marshal(jsonWord{
+			English:       word.EnglishWord,
+			Transcription: word.Transcription,
+			PartOfSpeech:  word.PartOfSpeech,
+			Translations:  word.Translations(),
+			Examples:      word.ExampleList(),
+			Note:          word.Note,
+			Decks:         deckNames(word),
+			AddedAt:       word.CreatedAt.UTC(),
+			Progress: jsonProgress{
+				EaseFactor:     word.EaseFactor,
+				Interval:       word.Interval,
+				Repetitions:    word.Repetitions,
+				DueAt:          word.DueAt,
+				LastReviewedAt: word.LastReviewedAt,
+				LeitnerBox:     word.LeitnerBox,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, separator); err != nil {
+			return err
+		}
+		separator = ",\n"
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n]\n")
+	return err
+}
+
+// writeAnki writes a text file for Anki's File > Import: tab-separated
+// front, back and tags, with the file headers Anki reads the layout from.
+func writeAnki(w io.Writer, words Source) error {
+	if _, err := io.WriteString(w, "#separator:tab\n#html:true\n#tags column:3\n"); err != nil {
+		return err
+	}
+
+	return words(func(word *models.Word) error {
+		back := []string{ankiField(word.Translation)}
+		for _, example := range word.ExampleList() {
+			back = append(back, "<i>"+ankiField(example)+"</i>")
+		}
+		if word.Note != "" {
+			back = append(back, ankiField(word.Note))
+		}
+
+		var tags []string
+		for _, name := range deckNames(word) {
+			// Anki tags cannot contain spaces
+			tags = append(tags, strings.Join(strings.Fields(name), "_"))
+		}
+
+		_, err := fmt.Fprintf(w, "%s\t%s\t%s\n",
+			ankiField(word.Headword().String()), strings.Join(back, "<br>"), strings.Join(tags, " "))
+		return err
+	})
+}
+
+// ankiField escapes text for an HTML field of a tab-separated Anki file.
+func ankiField(s string) string {
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, "\t", " ")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func deckNames(word *models.Word) []string {
+	names := make([]string, 0, len(word.Decks))
+	for _, deck := range word.Decks {
+		names = append(names, deck.Name)
+	}
+	return names
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
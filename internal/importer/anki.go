@@ -0,0 +1,183 @@
+package importer
+
+import (
+	"archive/zip"
+	"english-words-bot/internal/models"
+	"english-words-bot/internal/parser"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	// ErrAnkiFormat means the file is not an Anki package the importer can
+	// read.
+	ErrAnkiFormat = errors.New("not a supported Anki package")
+	// ErrTooLarge means the collection of an Anki package unpacks to more
+	// than maxCollectionSize.
+	ErrTooLarge = errors.New("the Anki collection is too large")
+)
+
+// ankiCollections are the collection files of an .apkg, newest format
+// first.
+var ankiCollections = []string{"collection.anki21", "collection.anki2"}
+
+// ankiCompressedCollection is the collection written by Anki 2.1.50 and
+// later unless "Support older Anki versions" is turned on on export. It is
+// compressed with zstd, and the collection.anki2 next to it only holds a
+// note asking to update Anki, so such packages are rejected.
+const ankiCompressedCollection = "collection.anki21b"
+
+// maxCollectionSize limits the unpacked size of a collection, so a small
+// upload cannot fill the disk.
+const maxCollectionSize = 200 << 20
+
+// ankiFieldSeparator separates the fields of an Anki note.
+const ankiFieldSeparator = "\x1f"
+
+var (
+	htmlBreak = regexp.MustCompile(`(?i)<br\s*/?>|</div>|</p>`)
+	htmlTag   = regexp.MustCompile(`<[^>]*>`)
+	ankiSound = regexp.MustCompile(`\[sound:[^\]]*\]`)
+)
+
+type ankiNote struct {
+	Flds string
+	Tags string
+}
+
+// ReadApkg reads the notes of an Anki package: the first field becomes the
+// English word, the second the translation, and the tags become decks.
+func ReadApkg(path string) (*File, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, ErrAnkiFormat
+	}
+	defer archive.Close()
+
+	files := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+	if files[ankiCompressedCollection] != nil && files["collection.anki21"] == nil {
+		return nil, ErrAnkiFormat
+	}
+
+	var collection *zip.File
+	for _, name := range ankiCollections {
+		if collection = files[name]; collection != nil {
+			break
+		}
+	}
+	if collection == nil {
+		return nil, ErrAnkiFormat
+	}
+	if collection.UncompressedSize64 > maxCollectionSize {
+		return nil, ErrTooLarge
+	}
+
+	// SQLite needs the collection as a file of its own
+	tmp, err := os.CreateTemp("", "collection-*.anki2")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if err := extract(collection, tmp); err != nil {
+		return nil, err
+	}
+
+	notes, err := readAnkiNotes(tmp.Name())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAnkiFormat, err)
+	}
+	if len(notes) == 0 {
+		return nil, ErrEmpty
+	}
+
+	file := &File{Format: "Anki package", Sides: [2]string{"Front", "Back"}}
+	for i, note := range notes {
+		fields := strings.Split(note.Flds, ankiFieldSeparator)
+		if len(fields) < 2 {
+			fields = append(fields, "")
+		}
+
+		var decks []string
+		for _, tag := range strings.Fields(note.Tags) {
+			// Tags cannot contain spaces, so words are joined with underscores
+			decks = append(decks, strings.ReplaceAll(tag, "_", " "))
+		}
+
+		// Lines of the back are usually alternative translations
+		front, back := ankiText(fields[0], " "), ankiText(fields[1], models.TranslationSeparator)
+		row, err := newRow(i+1, front, back, "", decks)
+		if err != nil {
+			file.Errors = append(file.Errors, &parser.LineError{Line: i + 1, Text: front + " - " + back, Err: err})
+			continue
+		}
+		file.Rows = append(file.Rows, row)
+	}
+	return file, nil
+}
+
+// extract copies a file out of the archive and closes the destination. The
+// size in the archive header is not trusted, so copying stops past
+// maxCollectionSize.
+func extract(f *zip.File, dst *os.File) error {
+	src, err := f.Open()
+	if err != nil {
+		dst.Close()
+		return err
+	}
+	defer src.Close()
+
+	n, err := io.Copy(dst, io.LimitReader(src, maxCollectionSize+1))
+	if err != nil {
+		dst.Close()
+		return err
+	}
+	if n > maxCollectionSize {
+		dst.Close()
+		return ErrTooLarge
+	}
+	return dst.Close()
+}
+
+func readAnkiNotes(path string) ([]ankiNote, error) {
+	collection, err := gorm.Open(sqlite.Open("file:"+path+"?mode=ro"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if conn, err := collection.DB(); err == nil {
+		defer conn.Close()
+	}
+
+	var notes []ankiNote
+	err = collection.Raw("SELECT flds, tags FROM notes ORDER BY id").Scan(&notes).Error
+	return notes, err
+}
+
+// ankiText turns the HTML of an Anki field into plain text, joining its
+// lines with the separator.
+func ankiText(field, separator string) string {
+	field = ankiSound.ReplaceAllString(field, "")
+	field = htmlBreak.ReplaceAllString(field, "\n")
+	field = htmlTag.ReplaceAllString(field, "")
+
+	var lines []string
+	for _, line := range strings.Split(html.UnescapeString(field), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, separator)
+}
@@ -0,0 +1,289 @@
+// Package importer reads word lists from files users upload.
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"english-words-bot/internal/models"
+	"english-words-bot/internal/parser"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Columns of a word list.
+const (
+	ColumnEnglish = iota
+	ColumnTranslation
+	ColumnNote
+	ColumnDeck
+	columnCount
+)
+
+// columnNames are the header names recognized for each column.
+var columnNames = map[string]int{
+	"english":      ColumnEnglish,
+	"english word": ColumnEnglish,
+	"word":         ColumnEnglish,
+	"front":        ColumnEnglish,
+	"term":         ColumnEnglish,
+	"translation":  ColumnTranslation,
+	"translations": ColumnTranslation,
+	"back":         ColumnTranslation,
+	"definition":   ColumnTranslation,
+	"meaning":      ColumnTranslation,
+	"note":         ColumnNote,
+	"notes":        ColumnNote,
+	"deck":         ColumnDeck,
+	"decks":        ColumnDeck,
+	"tag":          ColumnDeck,
+	"tags":         ColumnDeck,
+}
+
+// sampleLines is how many lines are looked at to detect the delimiter.
+const sampleLines = 10
+
+// Row is one word read from a file.
+type Row struct {
+	Line        int // 1-based line number in the file
+	Headword    models.Headword
+	Translation string // translations joined with models.JoinTranslations
+	Note        string
+	Decks       []string
+}
+
+// File is the content of an uploaded word list.
+type File struct {
+	Format    string    // human-readable description of the detected format
+	Sides     [2]string // names of the fields read as the English word and the translation
+	Delimiter rune      // 0 when the lines are read with the parser package
+	Header    bool
+	Rows      []Row
+	Errors    []*parser.LineError
+}
+
+// ErrEmpty means the file has no rows.
+var ErrEmpty = errors.New("the file is empty")
+
+// ReadDelimited reads a .csv, .tsv or .txt word list. The extension, with
+// the dot, tells how the delimiter is detected: .tsv files are tab separated,
+// .csv files may use commas, semicolons or tabs, .txt files holding a single
+// line of tab and semicolon separated cards are read as a Quizlet export, and
+// other .txt files without tabs are read line by line like words typed into
+// the chat. A first row naming
+// the columns (english, translation, note, deck) is used as a header,
+// otherwise the columns are taken in that order.
+func ReadDelimited(r io.Reader, ext string) (*File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 BOM
+	if strings.TrimSpace(string(data)) == "" {
+		return nil, ErrEmpty
+	}
+
+	var delimiter rune
+	switch strings.ToLower(ext) {
+	case ".tsv":
+		delimiter = '\t'
+	case ".csv":
+		delimiter = detectDelimiter(data)
+	default:
+		if isQuizletRows(data) {
+			return readQuizletRows(data), nil
+		}
+		if firstLine(data, '\t') {
+			delimiter = '\t'
+		}
+	}
+
+	if delimiter == 0 {
+		entries, errs := parser.Parse(string(data))
+		file := &File{Format: "word list", Sides: [2]string{"Word", "Translation"}, Errors: errs}
+		for _, entry := range entries {
+			file.Rows = append(file.Rows, Row{Line: entry.Line, Headword: entry.Headword, Translation: entry.Translation})
+		}
+		return file, nil
+	}
+	return readRecords(data, delimiter)
+}
+
+// readRecords reads a delimited file with an optional header row.
+func readRecords(data []byte, delimiter rune) (*File, error) {
+	reader := newReader(data, delimiter)
+	file := &File{
+		Format:    delimiterName(delimiter) + " separated",
+		Sides:     [2]string{"Column 1", "Column 2"},
+		Delimiter: delimiter,
+	}
+
+	columns := []int{ColumnEnglish, ColumnTranslation, ColumnNote, ColumnDeck}
+	first := true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				file.Errors = append(file.Errors, &parser.LineError{Line: parseErr.Line, Err: parseErr.Err})
+				continue
+			}
+			return nil, err
+		}
+
+		if first {
+			first = false
+			if header, ok := headerColumns(record); ok {
+				file.Header = true
+				columns = header
+				for i, column := range columns {
+					if column == ColumnEnglish || column == ColumnTranslation {
+						file.Sides[column] = strings.TrimSpace(record[i])
+					}
+				}
+				continue
+			}
+		}
+
+		row, err := recordRow(record, columns)
+		if err != nil {
+			file.Errors = append(file.Errors, &parser.LineError{
+				Line: line,
+				Text: strings.Join(record, string(delimiter)),
+				Err:  err,
+			})
+			continue
+		}
+		row.Line = line
+		file.Rows = append(file.Rows, row)
+	}
+	return file, nil
+}
+
+// recordRow builds a row out of the cells of a record.
+func recordRow(record []string, columns []int) (Row, error) {
+	var cells [columnCount]string
+	for i, cell := range record {
+		if i < len(columns) && columns[i] >= 0 {
+			cells[columns[i]] = strings.TrimSpace(cell)
+		}
+	}
+	return newRow(0, cells[ColumnEnglish], cells[ColumnTranslation], cells[ColumnNote], splitDecks(cells[ColumnDeck]))
+}
+
+// newRow builds a row, checking that the word and the translation are there.
+func newRow(line int, english, translation, note string, decks []string) (Row, error) {
+	row := Row{
+		Line:        line,
+		Headword:    models.ParseHeadword(english),
+		Translation: models.JoinTranslations(models.SplitTranslations(translation)),
+		Note:        strings.TrimSpace(note),
+		Decks:       decks,
+	}
+	switch {
+	case row.Headword.EnglishWord == "":
+		return Row{}, parser.ErrNoWord
+	case row.Translation == "":
+		return Row{}, parser.ErrNoTranslation
+	}
+	return row, nil
+}
+
+// splitDecks splits a list of deck names separated by semicolons or commas.
+func splitDecks(s string) []string {
+	var decks []string
+	for _, name := range strings.FieldsFunc(s, func(r rune) bool { return r == ';' || r == ',' }) {
+		if name = strings.TrimSpace(name); name != "" {
+			decks = append(decks, name)
+		}
+	}
+	return decks
+}
+
+// headerColumns maps the cells of a header row to columns. A header must name
+// the English word and the translation columns; unknown names are ignored.
+func headerColumns(record []string) ([]int, bool) {
+	columns := make([]int, len(record))
+	found := make(map[int]bool)
+	for i, cell := range record {
+		column, ok := columnNames[strings.ToLower(strings.TrimSpace(cell))]
+		if !ok {
+			columns[i] = -1
+			continue
+		}
+		columns[i] = column
+		found[column] = true
+	}
+	return columns, found[ColumnEnglish] && found[ColumnTranslation]
+}
+
+// detectDelimiter picks the delimiter that splits each of the first lines
+// into at least two fields, preferring one that gives every line the same
+// number of fields, then tabs, semicolons and commas in that order. Quotes
+// are parsed strictly here, so a delimiter that breaks quoted fields apart
+// does not fit. It returns 0 if no delimiter fits.
+func detectDelimiter(data []byte) rune {
+	var fallback rune
+	for _, delimiter := range []rune{'\t', ';', ','} {
+		reader := newReader(data, delimiter)
+		reader.LazyQuotes = false
+		fields := 0
+		valid, consistent := true, true
+		for i := 0; i < sampleLines; i++ {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil || len(record) < 2 {
+				valid = false
+				break
+			}
+			if fields != 0 && len(record) != fields {
+				consistent = false
+			}
+			fields = len(record)
+		}
+
+		switch {
+		case !valid || fields == 0:
+		case consistent:
+			return delimiter
+		case fallback == 0:
+			fallback = delimiter
+		}
+	}
+	return fallback
+}
+
+func newReader(data []byte, delimiter rune) *csv.Reader {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	return reader
+}
+
+// firstLine tells whether the first non-empty line contains the delimiter.
+func firstLine(data []byte, delimiter rune) bool {
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" {
+			return strings.ContainsRune(line, delimiter)
+		}
+	}
+	return false
+}
+
+func delimiterName(delimiter rune) string {
+	switch delimiter {
+	case '\t':
+		return "Tab"
+	case ';':
+		return "Semicolon"
+	default:
+		return "Comma"
+	}
+}
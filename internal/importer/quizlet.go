@@ -0,0 +1,58 @@
+package importer
+
+import (
+	"english-words-bot/internal/parser"
+	"strings"
+)
+
+// isQuizletRows tells whether a text file is a Quizlet export with tabs
+// between term and definition and semicolons between cards, which puts the
+// whole set on one line. It takes at least two cards, each with a tab, so a
+// single "word<tab>translation1; translation2" line is not mistaken for one.
+func isQuizletRows(data []byte) bool {
+	lines := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines++
+		}
+	}
+	if lines != 1 {
+		return false
+	}
+
+	cards := 0
+	for _, card := range strings.Split(strings.TrimSpace(string(data)), ";") {
+		if strings.TrimSpace(card) == "" {
+			continue
+		}
+		if !strings.Contains(card, "\t") {
+			return false
+		}
+		cards++
+	}
+	return cards >= 2
+}
+
+// readQuizletRows reads a Quizlet export with semicolons between cards.
+// Line numbers are card numbers.
+func readQuizletRows(data []byte) *File {
+	file := &File{Format: "Quizlet export", Delimiter: '\t', Sides: [2]string{"Term", "Definition"}}
+	for i, card := range strings.Split(strings.TrimSpace(string(data)), ";") {
+		if strings.TrimSpace(card) == "" {
+			continue
+		}
+
+		term, definition, ok := strings.Cut(card, "\t")
+		if !ok {
+			file.Errors = append(file.Errors, &parser.LineError{Line: i + 1, Text: strings.TrimSpace(card), Err: parser.ErrNoSeparator})
+			continue
+		}
+		row, err := newRow(i+1, term, definition, "", nil)
+		if err != nil {
+			file.Errors = append(file.Errors, &parser.LineError{Line: i + 1, Text: strings.TrimSpace(card), Err: err})
+			continue
+		}
+		file.Rows = append(file.Rows, row)
+	}
+	return file
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Deck is a named group of words, such as "Work" or "Travel".
+type Deck struct {
+	gorm.Model
+	UserID uint `gorm:"index"`
+	Name   string
+	Train  bool   // the deck is selected for training
+	Words  []Word `gorm:"many2many:word_decks;"`
+}
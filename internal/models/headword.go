@@ -0,0 +1,87 @@
+package models
+
+import "strings"
+
+// Headword is the English side of a word: the word itself with an optional
+// IPA transcription and part of speech, as in "record /ˈrek.ɔːd/ (n.)".
+type Headword struct {
+	EnglishWord   string
+	Transcription string // without the surrounding slashes
+	PartOfSpeech  string // one of PartsOfSpeech
+}
+
+// PartsOfSpeech lists the supported part-of-speech abbreviations.
+var PartsOfSpeech = []string{"n.", "v.", "adj.", "adv.", "prep.", "pron.", "conj.", "interj.", "phr."}
+
+var partOfSpeechNames = map[string]string{
+	"n": "n.", "noun": "n.",
+	"v": "v.", "verb": "v.",
+	"adj": "adj.", "adjective": "adj.",
+	"adv": "adv.", "adverb": "adv.",
+	"prep": "prep.", "preposition": "prep.",
+	"pron": "pron.", "pronoun": "pron.",
+	"conj": "conj.", "conjunction": "conj.",
+	"interj": "interj.", "interjection": "interj.",
+	"phr": "phr.", "phrase": "phr.",
+}
+
+// NormalizePartOfSpeech returns the abbreviation for a part of speech written
+// as "n", "n." or "noun", and false if it is not one of PartsOfSpeech.
+func NormalizePartOfSpeech(s string) (string, bool) {
+	pos, ok := partOfSpeechNames[strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), ".")]
+	return pos, ok
+}
+
+// ParseHeadword reads an English word optionally followed by a transcription
+// in slashes or brackets and a part of speech in parentheses, in any order:
+// "record /ˈrek.ɔːd/ (n.)". Parentheses that do not hold a known part of
+// speech are kept as part of the word.
+func ParseHeadword(s string) Headword {
+	var h Headword
+	rest := strings.TrimSpace(s)
+	for {
+		switch {
+		case h.PartOfSpeech == "" && strings.HasSuffix(rest, ")"):
+			open := strings.LastIndex(rest, "(")
+			if open < 0 {
+				break
+			}
+			pos, ok := NormalizePartOfSpeech(rest[open+1 : len(rest)-1])
+			if !ok {
+				break
+			}
+			h.PartOfSpeech = pos
+			rest = strings.TrimSpace(rest[:open])
+			continue
+
+		case h.Transcription == "" && (strings.HasSuffix(rest, "/") || strings.HasSuffix(rest, "]")):
+			opening := "/"
+			if strings.HasSuffix(rest, "]") {
+				opening = "["
+			}
+			open := strings.LastIndex(rest[:len(rest)-1], opening)
+			if open < 0 {
+				break
+			}
+			h.Transcription = strings.TrimSpace(rest[open+1 : len(rest)-1])
+			rest = strings.TrimSpace(rest[:open])
+			continue
+		}
+		break
+	}
+
+	h.EnglishWord = strings.Join(strings.Fields(rest), " ")
+	return h
+}
+
+// String formats the headword as "record /ˈrek.ɔːd/ (n.)".
+func (h Headword) String() string {
+	s := h.EnglishWord
+	if h.Transcription != "" {
+		s += " /" + h.Transcription + "/"
+	}
+	if h.PartOfSpeech != "" {
+		s += " (" + h.PartOfSpeech + ")"
+	}
+	return s
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// ReviewLog is one training answer, kept for history-based statistics.
+type ReviewLog struct {
+	gorm.Model
+	UserID    uint `gorm:"index"`
+	WordID    uint `gorm:"index"`
+	Direction string
+	Mode      string
+	Answer    string
+	Correct   bool
+	LatencyMs int64
+}
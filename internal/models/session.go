@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// Session is the persisted dialog state of a user: the step they are on and
+// an in-progress training session, so both survive bot restarts.
+type Session struct {
+	TelegramID   int64 `gorm:"primaryKey;autoIncrement:false"`
+	State        string
+	WordID       uint   // word currently asked in training
+	WordIDs      string // comma-separated training queue
+	CurrentIndex int
+	Correct      int
+	Incorrect    int
+	Direction    string
+	Reversed     bool
+	AskedAt      time.Time
+	UpdatedAt    time.Time
+}
@@ -10,4 +10,13 @@ type User struct {
 	TelegramID int64 `gorm:"uniqueIndex"`
 	Username   string
 	Words      []Word `gorm:"foreignKey:UserID"`
+
+	// Default number of words in an "N words" training session
+	SessionLength int `gorm:"default:10"`
+
+	// Part of speech to train on, empty for all words
+	TrainingPartOfSpeech string
+
+	// Sorting and filters of "My Words"
+	WordList WordListView `gorm:"embedded;embeddedPrefix:list_"`
 }
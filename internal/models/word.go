@@ -1,13 +1,87 @@
 package models
 
 import (
+	"strings"
+	"time"
+
 	"gorm.io/gorm"
 )
 
+// TranslationSeparator separates the accepted translations of a word, as in
+// "bank - берег; банк".
+const TranslationSeparator = ";"
+
 type Word struct {
 	gorm.Model
-	UserID       uint
-	EnglishWord  string
-	Translation  string
-	User         User `gorm:"foreignKey:UserID"`
-} 
\ No newline at end of file
+	UserID      uint
+	EnglishWord string
+	Translation string // one or more translations joined by TranslationSeparator
+	Examples    string // example sentences, one per line
+	Note        string
+
+	// Optional IPA transcription (without slashes) and part of speech
+	// abbreviation, see PartsOfSpeech
+	Transcription string
+	PartOfSpeech  string
+
+	// Spaced repetition (SM-2) state
+	EaseFactor     float64 `gorm:"default:2.5"`
+	Interval       int
+	Repetitions    int
+	DueAt          *time.Time `gorm:"index"`
+	LastReviewedAt *time.Time
+
+	// Leitner system state
+	LeitnerBox   int        `gorm:"default:1"`
+	LeitnerDueAt *time.Time `gorm:"index"`
+
+	User  User   `gorm:"foreignKey:UserID"`
+	Decks []Deck `gorm:"many2many:word_decks;"`
+}
+
+// Headword returns the English side of the word.
+func (w *Word) Headword() Headword {
+	return Headword{
+		EnglishWord:   w.EnglishWord,
+		Transcription: w.Transcription,
+		PartOfSpeech:  w.PartOfSpeech,
+	}
+}
+
+// Translations returns every accepted translation of the word.
+func (w *Word) Translations() []string {
+	return SplitTranslations(w.Translation)
+}
+
+// ExampleList returns the example sentences of the word.
+func (w *Word) ExampleList() []string {
+	return SplitExamples(w.Examples)
+}
+
+// SplitExamples splits text into its trimmed, non-empty lines.
+func SplitExamples(s string) []string {
+	var list []string
+	for _, e := range strings.Split(s, "\n") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+// SplitTranslations splits a translation list into its trimmed, non-empty
+// items.
+func SplitTranslations(s string) []string {
+	var list []string
+	for _, t := range strings.Split(s, TranslationSeparator) {
+		if t = strings.TrimSpace(t); t != "" {
+			list = append(list, t)
+		}
+	}
+	return list
+}
+
+// JoinTranslations builds the stored form of a translation list.
+func JoinTranslations(list []string) string {
+	return strings.Join(list, TranslationSeparator+" ")
+}
@@ -0,0 +1,18 @@
+package models
+
+// Word list sort orders.
+const (
+	SortOldest        = "oldest"
+	SortNewest        = "newest"
+	SortAlphabetical  = "alphabetical"
+	SortMostMissed    = "most_missed"
+	SortLeastReviewed = "least_reviewed"
+)
+
+// WordListView is how a user's word list is sorted and filtered.
+type WordListView struct {
+	Sort         string // one of the Sort constants, SortOldest if empty
+	DeckID       uint   // only words in this deck, 0 for all words
+	AddedDays    int    // only words added in the last AddedDays days, 0 for all
+	NeverTrained bool   // only words that were never answered in training
+}
@@ -0,0 +1,176 @@
+// Package parser reads word lists typed by users, such as
+//
+//	well-known - відомий
+//	record /ˈrek.ɔːd/ (n.) — запис
+//	bank: берег; банк
+//
+// into entries ready to be added to a dictionary.
+package parser
+
+import (
+	"english-words-bot/internal/models"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrNoSeparator means a line has no separator between the English word
+	// and the translation.
+	ErrNoSeparator = errors.New("no separator between the word and the translation")
+	// ErrNoWord means the English word is missing.
+	ErrNoWord = errors.New("the English word is missing")
+	// ErrNoTranslation means the translation is missing.
+	ErrNoTranslation = errors.New("the translation is missing")
+)
+
+// separators split the English word from the translation. A hyphen only
+// counts with spaces on both sides, so hyphenated words such as "e-mail" stay
+// whole; the other separators count with a space on either side, or without
+// spaces when the line has no spaced separator at all.
+var separators = []string{"-", "—", "–", ":", "=", "\t"}
+
+// Entry is one word read from the input.
+type Entry struct {
+	Line        int // 1-based line number in the input
+	Headword    models.Headword
+	Translation string // translations joined with models.JoinTranslations
+}
+
+// LineError tells why a line, or an item of it, was rejected.
+type LineError struct {
+	Line int    // 1-based line number in the input
+	Text string // the rejected text
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %q: %v", e.Line, e.Text, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Parse reads one word per line. A line may also hold several words
+// separated by commas, as in "cat - кіт, dog - пес", when every item has a
+// separator; otherwise commas are kept as part of the translation. Empty
+// lines are skipped.
+func Parse(text string) ([]Entry, []*LineError) {
+	var entries []Entry
+	var errs []*LineError
+
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		for _, item := range splitItems(line) {
+			entry, err := ParseLine(item)
+			if err != nil {
+				errs = append(errs, &LineError{Line: i + 1, Text: strings.TrimSpace(item), Err: err})
+				continue
+			}
+			entry.Line = i + 1
+			entries = append(entries, entry)
+		}
+	}
+	return entries, errs
+}
+
+// ParseLine reads a single "english_word - translation" pair.
+func ParseLine(line string) (Entry, error) {
+	left, right, ok := split(line)
+	if !ok {
+		return Entry{}, ErrNoSeparator
+	}
+
+	headword := models.ParseHeadword(left)
+	if headword.EnglishWord == "" {
+		return Entry{}, ErrNoWord
+	}
+	translation := models.JoinTranslations(models.SplitTranslations(right))
+	if translation == "" {
+		return Entry{}, ErrNoTranslation
+	}
+	return Entry{Headword: headword, Translation: translation}, nil
+}
+
+// splitItems splits a line on commas when every part is a word pair.
+func splitItems(line string) []string {
+	items := strings.Split(line, ",")
+	if len(items) == 1 {
+		return items
+	}
+
+	var nonEmpty []string
+	for _, item := range items {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		if _, _, ok := split(item); !ok {
+			return []string{line}
+		}
+		nonEmpty = append(nonEmpty, item)
+	}
+	return nonEmpty
+}
+
+// split cuts a line at the first spaced separator, or failing that at the
+// first unspaced one other than a hyphen.
+func split(line string) (string, string, bool) {
+	at, size := -1, 0
+	for _, sep := range separators {
+		if i := findSpaced(line, sep); i >= 0 && (at < 0 || i < at) {
+			at, size = i, len(sep)
+		}
+	}
+
+	if at < 0 {
+		for _, sep := range separators[1:] {
+			if i := strings.Index(line, sep); i >= 0 && (at < 0 || i < at) {
+				at, size = i, len(sep)
+			}
+		}
+	}
+
+	if at < 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(line[:at]), strings.TrimSpace(line[at+size:]), true
+}
+
+// findSpaced returns the index of the first occurrence of sep that counts as
+// a spaced separator, or -1.
+func findSpaced(line, sep string) int {
+	for offset := 0; offset < len(line); {
+		i := strings.Index(line[offset:], sep)
+		if i < 0 {
+			return -1
+		}
+		i += offset
+
+		before := i == 0 || isSpaceBefore(line[:i])
+		after := i+len(sep) == len(line) || isSpaceAfter(line[i+len(sep):])
+		switch {
+		case sep == "\t",
+			sep == "-" && before && after,
+			sep != "-" && (before || after):
+			return i
+		}
+		offset = i + len(sep)
+	}
+	return -1
+}
+
+func isSpaceBefore(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return unicode.IsSpace(r)
+}
+
+func isSpaceAfter(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsSpace(r)
+}
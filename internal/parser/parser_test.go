@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+
+	"english-words-bot/internal/models"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line        string
+		headword    models.Headword
+		translation string
+		err         error
+	}{
+		{line: "cat - кіт", headword: models.Headword{EnglishWord: "cat"}, translation: "кіт"},
+		{line: "  cat   -   кіт  ", headword: models.Headword{EnglishWord: "cat"}, translation: "кіт"},
+		{line: "well-known - відомий", headword: models.Headword{EnglishWord: "well-known"}, translation: "відомий"},
+		{line: "mother-in-law — теща", headword: models.Headword{EnglishWord: "mother-in-law"}, translation: "теща"},
+		{line: "e-mail - лист", headword: models.Headword{EnglishWord: "e-mail"}, translation: "лист"},
+		{line: "cat — кіт", headword: models.Headword{EnglishWord: "cat"}, translation: "кіт"},
+		{line: "cat – кіт", headword: models.Headword{EnglishWord: "cat"}, translation: "кіт"},
+		{line: "cat: кіт", headword: models.Headword{EnglishWord: "cat"}, translation: "кіт"},
+		{line: "cat = кіт", headword: models.Headword{EnglishWord: "cat"}, translation: "кіт"},
+		{line: "cat\tкіт", headword: models.Headword{EnglishWord: "cat"}, translation: "кіт"},
+		// The first spaced separator wins, later ones stay in the translation.
+		{line: "ratio - співвідношення: 1:2", headword: models.Headword{EnglishWord: "ratio"}, translation: "співвідношення: 1:2"},
+		{line: "time: 10-11 - час", headword: models.Headword{EnglishWord: "time"}, translation: "10-11 - час"},
+		// A spaced separator is preferred to an earlier unspaced one.
+		{line: "a:b - ratio", headword: models.Headword{EnglishWord: "a:b"}, translation: "ratio"},
+		// Without spaced separators the first unspaced one is used, never a hyphen.
+		{line: "cat:кіт", headword: models.Headword{EnglishWord: "cat"}, translation: "кіт"},
+		{line: "cat=кіт", headword: models.Headword{EnglishWord: "cat"}, translation: "кіт"},
+		{line: "well-known:відомий", headword: models.Headword{EnglishWord: "well-known"}, translation: "відомий"},
+		{line: "bank - берег;  банк ;", headword: models.Headword{EnglishWord: "bank"}, translation: "берег; банк"},
+		{
+			line:        "record /ˈrek.ɔːd/ (n.) - запис",
+			headword:    models.Headword{EnglishWord: "record", Transcription: "ˈrek.ɔːd", PartOfSpeech: "n."},
+			translation: "запис",
+		},
+		{line: "well-known", err: ErrNoSeparator},
+		{line: "cat кіт", err: ErrNoSeparator},
+		{line: " - кіт", err: ErrNoWord},
+		{line: "cat - ", err: ErrNoTranslation},
+		{line: "cat - ;", err: ErrNoTranslation},
+	}
+
+	for _, tt := range tests {
+		entry, err := ParseLine(tt.line)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("ParseLine(%q) error = %v, want %v", tt.line, err, tt.err)
+			continue
+		}
+		if entry.Headword != tt.headword || entry.Translation != tt.translation {
+			t.Errorf("ParseLine(%q) = %+v %q, want %+v %q",
+				tt.line, entry.Headword, entry.Translation, tt.headword, tt.translation)
+		}
+	}
+}
+
+func TestParseCommas(t *testing.T) {
+	tests := []struct {
+		text  string
+		words []string
+		want  []string // translations
+	}{
+		{text: "cat - кіт, dog - пес", words: []string{"cat", "dog"}, want: []string{"кіт", "пес"}},
+		{text: "cat - кіт, dog - пес,", words: []string{"cat", "dog"}, want: []string{"кіт", "пес"}},
+		// An item without a separator keeps the commas in the translation.
+		{text: "bank - берег, банк", words: []string{"bank"}, want: []string{"берег, банк"}},
+		{text: "cat - кіт, dog - пес, мишка", words: []string{"cat"}, want: []string{"кіт, dog - пес, мишка"}},
+	}
+
+	for _, tt := range tests {
+		entries, errs := Parse(tt.text)
+		if len(errs) > 0 {
+			t.Errorf("Parse(%q) errors = %v", tt.text, errs)
+		}
+		if len(entries) != len(tt.words) {
+			t.Errorf("Parse(%q) = %d entries, want %d", tt.text, len(entries), len(tt.words))
+			continue
+		}
+		for i, entry := range entries {
+			if entry.Headword.EnglishWord != tt.words[i] || entry.Translation != tt.want[i] {
+				t.Errorf("Parse(%q)[%d] = %q - %q, want %q - %q",
+					tt.text, i, entry.Headword.EnglishWord, entry.Translation, tt.words[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseLineNumbers(t *testing.T) {
+	text := "cat - кіт\n\nwell-known\n  \ndog - пес, fox - лис\n - порожньо"
+	entries, errs := Parse(text)
+
+	wantEntries := map[string]int{"cat": 1, "dog": 5, "fox": 5}
+	if len(entries) != len(wantEntries) {
+		t.Fatalf("Parse() = %d entries, want %d", len(entries), len(wantEntries))
+	}
+	for _, entry := range entries {
+		if line := wantEntries[entry.Headword.EnglishWord]; entry.Line != line {
+			t.Errorf("%q on line %d, want %d", entry.Headword.EnglishWord, entry.Line, line)
+		}
+	}
+
+	wantErrs := []LineError{
+		{Line: 3, Text: "well-known", Err: ErrNoSeparator},
+		{Line: 6, Text: "- порожньо", Err: ErrNoWord},
+	}
+	if len(errs) != len(wantErrs) {
+		t.Fatalf("Parse() = %d errors, want %d: %v", len(errs), len(wantErrs), errs)
+	}
+	for i, err := range errs {
+		want := wantErrs[i]
+		if err.Line != want.Line || err.Text != want.Text || !errors.Is(err, want.Err) {
+			t.Errorf("error %d = %v, want %v", i, err, &want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"english-words-bot/internal/db"
+	"english-words-bot/internal/models"
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrDeckNotFound is returned when the user has no deck with that name or ID.
+	ErrDeckNotFound = errors.New("deck not found")
+	// ErrDeckExists is returned when the user already has a deck with that name.
+	ErrDeckExists = errors.New("deck already exists")
+)
+
+type DeckService struct{}
+
+// DeckInfo is a deck together with the number of words in it.
+type DeckInfo struct {
+	models.Deck
+	WordCount int64
+}
+
+func (s *DeckService) GetUserDecks(userID uint) ([]models.Deck, error) {
+	var decks []models.Deck
+	err := db.DB.Where("user_id = ?", userID).Order("name").Find(&decks).Error
+	return decks, err
+}
+
+// GetUserDeckInfos returns the user's decks with their word counts.
+func (s *DeckService) GetUserDeckInfos(userID uint) ([]DeckInfo, error) {
+	var infos []DeckInfo
+	err := db.DB.Model(&models.Deck{}).
+		Select("decks.*, COUNT(words.id) AS word_count").
+		Joins("LEFT JOIN word_decks ON word_decks.deck_id = decks.id").
+		Joins("LEFT JOIN words ON words.id = word_decks.word_id AND words.deleted_at IS NULL").
+		Where("decks.user_id = ?", userID).
+		Group("decks.id").
+		Order("decks.name").
+		Scan(&infos).Error
+	return infos, err
+}
+
+// FindDeck returns the user's deck with the given name, ignoring case.
+func (s *DeckService) FindDeck(userID uint, name string) (*models.Deck, error) {
+	decks, err := s.GetUserDecks(userID)
+	if err != nil {
+		return nil, err
+	}
+	name = normalizeSpaces(name)
+	for i := range decks {
+		if strings.EqualFold(decks[i].Name, name) {
+			return &decks[i], nil
+		}
+	}
+	return nil, ErrDeckNotFound
+}
+
+func (s *DeckService) GetDeckByID(userID, deckID uint) (*models.Deck, error) {
+	var decks []models.Deck
+	err := db.DB.Where("id = ? AND user_id = ?", deckID, userID).Limit(1).Find(&decks).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(decks) == 0 {
+		return nil, ErrDeckNotFound
+	}
+	return &decks[0], nil
+}
+
+func (s *DeckService) CreateDeck(userID uint, name string) (*models.Deck, error) {
+	name = normalizeSpaces(name)
+	if _, err := s.FindDeck(userID, name); err == nil {
+		return nil, ErrDeckExists
+	} else if !errors.Is(err, ErrDeckNotFound) {
+		return nil, err
+	}
+
+	deck := models.Deck{UserID: userID, Name: name}
+	return &deck, db.DB.Create(&deck).Error
+}
+
+func (s *DeckService) RenameDeck(userID uint, oldName, newName string) (*models.Deck, error) {
+	deck, err := s.FindDeck(userID, oldName)
+	if err != nil {
+		return nil, err
+	}
+
+	newName = normalizeSpaces(newName)
+	if other, err := s.FindDeck(userID, newName); err == nil && other.ID != deck.ID {
+		return nil, ErrDeckExists
+	}
+
+	deck.Name = newName
+	return deck, db.DB.Model(deck).Update("name", newName).Error
+}
+
+// DeleteDeck removes a deck; its words stay in the dictionary.
+func (s *DeckService) DeleteDeck(userID uint, name string) (*models.Deck, error) {
+	deck, err := s.FindDeck(userID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.DB.Model(deck).Association("Words").Clear(); err != nil {
+		return nil, err
+	}
+	// Показуємо всі слова, якщо список був відфільтрований за цією колодою
+	err = db.DB.Model(&models.User{}).Where("id = ? AND list_deck_id = ?", userID, deck.ID).
+		Update("list_deck_id", 0).Error
+	if err != nil {
+		return nil, err
+	}
+	return deck, db.DB.Delete(deck).Error
+}
+
+// AddWordToDeck puts a word into a deck; adding it twice is a no-op.
+func (s *DeckService) AddWordToDeck(deck *models.Deck, word *models.Word) error {
+	return db.DB.Model(deck).Association("Words").Append(word)
+}
+
+// ToggleTraining selects or deselects a deck for training.
+func (s *DeckService) ToggleTraining(userID, deckID uint) (*models.Deck, error) {
+	deck, err := s.GetDeckByID(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	deck.Train = !deck.Train
+	return deck, db.DB.Model(deck).Update("train", deck.Train).Error
+}
+
+// ClearTraining deselects all decks, so training uses the whole dictionary.
+func (s *DeckService) ClearTraining(userID uint) error {
+	return db.DB.Model(&models.Deck{}).Where("user_id = ?", userID).Update("train", false).Error
+}
@@ -0,0 +1,12 @@
+package services
+
+import (
+	"english-words-bot/internal/db"
+	"english-words-bot/internal/models"
+)
+
+type ReviewService struct{}
+
+func (s *ReviewService) LogReview(log *models.ReviewLog) error {
+	return db.DB.Create(log).Error
+}
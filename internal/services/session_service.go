@@ -0,0 +1,22 @@
+package services
+
+import (
+	"english-words-bot/internal/db"
+	"english-words-bot/internal/models"
+)
+
+type SessionService struct{}
+
+func (s *SessionService) SaveSession(session *models.Session) error {
+	return db.DB.Save(session).Error
+}
+
+func (s *SessionService) DeleteSession(telegramID int64) error {
+	return db.DB.Delete(&models.Session{}, telegramID).Error
+}
+
+func (s *SessionService) GetSessions() ([]models.Session, error) {
+	var sessions []models.Session
+	err := db.DB.Find(&sessions).Error
+	return sessions, err
+}
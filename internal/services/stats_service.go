@@ -0,0 +1,162 @@
+package services
+
+import (
+	"english-words-bot/internal/db"
+	"english-words-bot/internal/models"
+	"english-words-bot/internal/srs"
+	"time"
+)
+
+type StatsService struct{}
+
+// PeriodStats holds the activity numbers for one period of time.
+type PeriodStats struct {
+	WordsAdded int64
+	Reviews    int64
+	Correct    int64
+}
+
+// Accuracy is the share of correct answers in percent.
+func (p PeriodStats) Accuracy() float64 {
+	if p.Reviews == 0 {
+		return 0
+	}
+	return float64(p.Correct) / float64(p.Reviews) * 100
+}
+
+// HardWord is a word together with its answer history.
+type HardWord struct {
+	WordID      uint
+	EnglishWord string
+	Translation string
+	Reviews     int64
+	Mistakes    int64
+}
+
+type UserStats struct {
+	Lifetime      PeriodStats
+	Last7Days     PeriodStats
+	Last30Days    PeriodStats
+	TotalWords    int64
+	MasteredWords int64
+	CurrentStreak int // days in a row with at least one review
+	BestStreak    int
+	HardestWords  []HardWord
+}
+
+// GetUserStats builds long-term statistics from the stored words and the
+// review log.
+func (s *StatsService) GetUserStats(userID uint, now time.Time) (*UserStats, error) {
+	stats := &UserStats{}
+	var err error
+
+	if stats.Lifetime, err = s.periodStats(userID, time.Time{}); err != nil {
+		return nil, err
+	}
+	if stats.Last7Days, err = s.periodStats(userID, now.AddDate(0, 0, -7)); err != nil {
+		return nil, err
+	}
+	if stats.Last30Days, err = s.periodStats(userID, now.AddDate(0, 0, -30)); err != nil {
+		return nil, err
+	}
+
+	stats.TotalWords = stats.Lifetime.WordsAdded
+	err = db.DB.Model(&models.Word{}).
+		Where("user_id = ? AND interval >= ?", userID, srs.MasteredInterval).
+		Count(&stats.MasteredWords).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var reviewTimes []time.Time
+	err = db.DB.Model(&models.ReviewLog{}).Where("user_id = ?", userID).
+		Order("created_at").Pluck("created_at", &reviewTimes).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.CurrentStreak, stats.BestStreak = streaks(reviewTimes, now)
+
+	err = db.DB.Table("review_logs").
+		Select("review_logs.word_id, words.english_word, words.translation, "+
+			"COUNT(*) AS reviews, SUM(CASE WHEN review_logs.correct THEN 0 ELSE 1 END) AS mistakes").
+		Joins("JOIN words ON words.id = review_logs.word_id AND words.deleted_at IS NULL").
+		Where("review_logs.user_id = ? AND review_logs.deleted_at IS NULL", userID).
+		Group("review_logs.word_id, words.english_word, words.translation").
+		Having("mistakes > 0").
+		Order("CAST(mistakes AS REAL) / COUNT(*) DESC, mistakes DESC").
+		Limit(10).
+		Scan(&stats.HardestWords).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// periodStats counts the activity since the given time; a zero time means
+// the whole history.
+func (s *StatsService) periodStats(userID uint, since time.Time) (PeriodStats, error) {
+	var p PeriodStats
+
+	err := db.DB.Model(&models.Word{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&p.WordsAdded).Error
+	if err != nil {
+		return p, err
+	}
+
+	err = db.DB.Model(&models.ReviewLog{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&p.Reviews).Error
+	if err != nil {
+		return p, err
+	}
+
+	err = db.DB.Model(&models.ReviewLog{}).
+		Where("user_id = ? AND created_at >= ? AND correct = ?", userID, since, true).
+		Count(&p.Correct).Error
+	return p, err
+}
+
+// streaks returns the current and the best number of consecutive days with
+// reviews. The current streak is still alive if the last review was
+// yesterday.
+func streaks(reviewTimes []time.Time, now time.Time) (current, best int) {
+	days := make(map[time.Time]bool)
+	var ordered []time.Time
+	for _, t := range reviewTimes {
+		day := truncateDay(t.In(now.Location()))
+		if !days[day] {
+			days[day] = true
+			ordered = append(ordered, day)
+		}
+	}
+
+	run := 0
+	var prev time.Time
+	for _, day := range ordered {
+		if run > 0 && prev.AddDate(0, 0, 1).Equal(day) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > best {
+			best = run
+		}
+		prev = day
+	}
+
+	day := truncateDay(now)
+	if !days[day] {
+		day = day.AddDate(0, 0, -1)
+	}
+	for days[day] {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+	return current, best
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -10,7 +10,7 @@ type UserService struct{}
 func (s *UserService) GetOrCreateUser(telegramID int64, username string) (*models.User, error) {
 	var user models.User
 	result := db.DB.Where("telegram_id = ?", telegramID).First(&user)
-	
+
 	if result.Error != nil {
 		user = models.User{
 			TelegramID: telegramID,
@@ -20,6 +20,30 @@ func (s *UserService) GetOrCreateUser(telegramID int64, username string) (*model
 			return nil, err
 		}
 	}
-	
+
 	return &user, nil
-} 
\ No newline at end of file
+}
+
+// SetSessionLength sets the default number of words in an "N words" session.
+func (s *UserService) SetSessionLength(userID uint, length int) error {
+	return db.DB.Model(&models.User{}).Where("id = ?", userID).
+		Update("session_length", length).Error
+}
+
+// SetWordListView saves how the user's word list is sorted and filtered.
+func (s *UserService) SetWordListView(userID uint, view models.WordListView) error {
+	return db.DB.Model(&models.User{}).Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"list_sort":          view.Sort,
+			"list_deck_id":       view.DeckID,
+			"list_added_days":    view.AddedDays,
+			"list_never_trained": view.NeverTrained,
+		}).Error
+}
+
+// SetTrainingPartOfSpeech limits training to one part of speech, or to all
+// words if partOfSpeech is empty.
+func (s *UserService) SetTrainingPartOfSpeech(userID uint, partOfSpeech string) error {
+	return db.DB.Model(&models.User{}).Where("id = ?", userID).
+		Update("training_part_of_speech", partOfSpeech).Error
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"english-words-bot/internal/db"
+	"english-words-bot/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ImportEntry is a word to import together with its note and the names of
+// the decks to put it in.
+type ImportEntry struct {
+	Headword    models.Headword
+	Translation string
+	Note        string
+	Decks       []string
+}
+
+// ImportResult counts what ImportWords did with the entries.
+type ImportResult struct {
+	Added      int
+	Duplicates int
+}
+
+// ImportWords adds the entries in one transaction, creating missing decks.
+// Entries the user already has, including earlier entries of the same import,
+// are skipped as duplicates; nothing is merged into existing words.
+func (s *WordService) ImportWords(userID uint, entries []ImportEntry) (ImportResult, error) {
+	var result ImportResult
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var existing []models.Deck
+		if err := tx.Where("user_id = ?", userID).Find(&existing).Error; err != nil {
+			return err
+		}
+		decks := make(map[string]*models.Deck)
+		for i := range existing {
+			decks[strings.ToLower(existing[i].Name)] = &existing[i]
+		}
+
+		for _, entry := range entries {
+			entry.Headword.EnglishWord = normalizeSpaces(entry.Headword.EnglishWord)
+			found, err := findWord(tx, userID, entry.Headword)
+			if err != nil {
+				return err
+			}
+			if found != nil {
+				result.Duplicates++
+				continue
+			}
+
+			word := models.Word{
+				UserID:        userID,
+				EnglishWord:   entry.Headword.EnglishWord,
+				Translation:   entry.Translation,
+				Transcription: entry.Headword.Transcription,
+				PartOfSpeech:  entry.Headword.PartOfSpeech,
+				Note:          strings.TrimSpace(entry.Note),
+			}
+			if err := tx.Create(&word).Error; err != nil {
+				return err
+			}
+
+			for _, name := range entry.Decks {
+				if name = normalizeSpaces(name); name == "" {
+					continue
+				}
+				deck, ok := decks[strings.ToLower(name)]
+				if !ok {
+					deck = &models.Deck{UserID: userID, Name: name}
+					if err := tx.Create(deck).Error; err != nil {
+						return err
+					}
+					decks[strings.ToLower(name)] = deck
+				}
+				if err := tx.Model(deck).Association("Words").Append(&word); err != nil {
+					return err
+				}
+			}
+			result.Added++
+		}
+		return nil
+	})
+	if err != nil {
+		return ImportResult{}, err
+	}
+	return result, nil
+}
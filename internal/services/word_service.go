@@ -3,45 +3,520 @@ package services
 import (
 	"english-words-bot/internal/db"
 	"english-words-bot/internal/models"
+	"english-words-bot/internal/srs"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type WordService struct{}
 
-func (s *WordService) AddWord(userID uint, englishWord, translation string) error {
+// ErrWordNotFound means the word does not exist, is in the trash or belongs
+// to another user.
+var ErrWordNotFound = errors.New("word not found")
+
+// ErrWordExists means a word cannot be restored from the trash because the
+// user has added the same word again since.
+var ErrWordExists = errors.New("word already in dictionary")
+
+// AddResult tells what AddWord did with a word.
+type AddResult int
+
+const (
+	// WordAdded means the word was new and has been inserted.
+	WordAdded AddResult = iota
+	// WordDuplicate means the word already exists with all the given
+	// translations, so nothing was inserted.
+	WordDuplicate
+	// WordConflict means the word already exists with other translations;
+	// nothing was inserted, the new ones can be merged with MergeTranslations.
+	WordConflict
+)
+
+// AddWord inserts a word unless the user already has it. Existing words are
+// matched on the English word, ignoring case and extra whitespace, and on the
+// part of speech, so homographs such as "record (n.)" and "record (v.)" are
+// separate words. For duplicates and conflicts the existing word is returned.
+func (s *WordService) AddWord(userID uint, headword models.Headword, translation string) (AddResult, *models.Word, error) {
+	headword.EnglishWord = normalizeSpaces(headword.EnglishWord)
+
+	existing, err := s.FindWord(userID, headword)
+	if err != nil {
+		return WordAdded, nil, err
+	}
+	if existing != nil {
+		if len(missingTranslations(existing, models.SplitTranslations(translation))) == 0 {
+			return WordDuplicate, existing, nil
+		}
+		return WordConflict, existing, nil
+	}
+
 	word := models.Word{
-		UserID:      userID,
-		EnglishWord: englishWord,
-		Translation: translation,
+		UserID:        userID,
+		EnglishWord:   headword.EnglishWord,
+		Translation:   translation,
+		Transcription: headword.Transcription,
+		PartOfSpeech:  headword.PartOfSpeech,
+	}
+	return WordAdded, &word, db.DB.Create(&word).Error
+}
+
+// FindWord returns the user's word with the given English word, ignoring case
+// and extra whitespace, or nil if there is none. A word without a part of
+// speech matches any part of speech, words with the same one are preferred.
+func (s *WordService) FindWord(userID uint, headword models.Headword) (*models.Word, error) {
+	return findWord(db.DB, userID, headword)
+}
+
+func findWord(tx *gorm.DB, userID uint, headword models.Headword) (*models.Word, error) {
+	var words []models.Word
+	err := tx.Where("user_id = ? AND LOWER(TRIM(english_word)) = ?",
+		userID, strings.ToLower(normalizeSpaces(headword.EnglishWord))).
+		Where("(part_of_speech = ? OR part_of_speech = '' OR ? = '')", headword.PartOfSpeech, headword.PartOfSpeech).
+		Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "part_of_speech = ? DESC, id",
+			Vars: []interface{}{headword.PartOfSpeech},
+		}}).
+		Limit(1).Find(&words).Error
+	if err != nil || len(words) == 0 {
+		return nil, err
 	}
-	return db.DB.Create(&word).Error
+	return &words[0], nil
+}
+
+// MergeTranslations adds the translations the user's word does not have yet.
+func (s *WordService) MergeTranslations(userID, wordID uint, translations []string) (*models.Word, error) {
+	word, err := s.GetWordByID(userID, wordID)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := missingTranslations(word, translations)
+	if len(missing) == 0 {
+		return word, nil
+	}
+	word.Translation = models.JoinTranslations(append(word.Translations(), missing...))
+	return word, db.DB.Model(word).Update("translation", word.Translation).Error
+}
+
+// missingTranslations returns the translations the word does not have,
+// ignoring case.
+func missingTranslations(word *models.Word, translations []string) []string {
+	have := make(map[string]bool)
+	for _, t := range word.Translations() {
+		have[strings.ToLower(t)] = true
+	}
+
+	var missing []string
+	for _, t := range translations {
+		if key := strings.ToLower(t); !have[key] {
+			have[key] = true
+			missing = append(missing, t)
+		}
+	}
+	return missing
+}
+
+func normalizeSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
 }
 
 func (s *WordService) GetUserWords(userID uint) ([]models.Word, error) {
 	var words []models.Word
-	err := db.DB.Where("user_id = ?", userID).Find(&words).Error
+	err := db.DB.Where("user_id = ?", userID).Order("id").Find(&words).Error
 	return words, err
 }
 
-func (s *WordService) UpdateWord(wordID uint, englishWord, translation string) error {
-	return db.DB.Model(&models.Word{}).Where("id = ?", wordID).
-		Updates(map[string]interface{}{
-			"english_word": englishWord,
-			"translation":  translation,
+// exportBatchSize is how many words EachWord loads at a time.
+const exportBatchSize = 500
+
+// EachWord calls fn for every word of the user, with its decks, loading the
+// dictionary in batches so it never has to be held in memory at once.
+func (s *WordService) EachWord(userID uint, fn func(*models.Word) error) error {
+	var batch []models.Word
+	var fnErr error
+	err := db.DB.Preload("Decks").Where("user_id = ?", userID).
+		FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+			for i := range batch {
+				if fnErr = fn(&batch[i]); fnErr != nil {
+					return fnErr
+				}
+			}
+			return nil
 		}).Error
+	if fnErr != nil {
+		return fnErr
+	}
+	return err
+}
+
+// GetUserWordsPage returns one page of the user's words, sorted and filtered
+// as the view says, together with the total number of matching words.
+func (s *WordService) GetUserWordsPage(userID uint, view models.WordListView, page, pageSize int) ([]models.Word, int64, error) {
+	words := viewWords(userID, view, time.Now())
+
+	var total int64
+	if err := words.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var list []models.Word
+	err := words.Session(&gorm.Session{}).Clauses(viewOrder(view.Sort)).
+		Offset(page * pageSize).Limit(pageSize).
+		Find(&list).Error
+	return list, total, err
+}
+
+// viewWords starts a query over the user's words matching the filters of
+// the view.
+func viewWords(userID uint, view models.WordListView, now time.Time) *gorm.DB {
+	words := db.DB.Model(&models.Word{}).Where("user_id = ?", userID)
+	if view.DeckID != 0 {
+		inDeck := db.DB.Table("word_decks").Select("word_id").Where("deck_id = ?", view.DeckID)
+		words = words.Where("id IN (?)", inDeck)
+	}
+	if view.AddedDays > 0 {
+		words = words.Where("created_at >= ?", now.AddDate(0, 0, -view.AddedDays))
+	}
+	if view.NeverTrained {
+		words = words.Where("last_reviewed_at IS NULL")
+	}
+	return words
+}
+
+// viewOrder sorts a word list, oldest words first by default.
+func viewOrder(sort string) clause.OrderBy {
+	var order clause.Expr
+	switch sort {
+	case models.SortNewest:
+		order = clause.Expr{SQL: "id DESC"}
+	case models.SortAlphabetical:
+		order = clause.Expr{SQL: "LOWER(english_word), id"}
+	case models.SortMostMissed:
+		order = clause.Expr{
+			SQL: "(SELECT COUNT(*) FROM review_logs WHERE review_logs.word_id = words.id " +
+				"AND review_logs.correct = ? AND review_logs.deleted_at IS NULL) DESC, id",
+			Vars: []interface{}{false},
+		}
+	case models.SortLeastReviewed:
+		// Слова, які ще не тренувались, показуємо першими
+		order = clause.Expr{SQL: "last_reviewed_at IS NOT NULL, last_reviewed_at, id"}
+	default:
+		order = clause.Expr{SQL: "id"}
+	}
+	return clause.OrderBy{Expression: order}
+}
+
+func (s *WordService) UpdateWord(userID, wordID uint, headword models.Headword, translation string) error {
+	return updateWord(userID, wordID, map[string]interface{}{
+		"english_word":   headword.EnglishWord,
+		"translation":    translation,
+		"transcription":  headword.Transcription,
+		"part_of_speech": headword.PartOfSpeech,
+	})
+}
+
+// UpdateHeadword replaces the English word of the user's word together with
+// its transcription and part of speech, keeping the translations.
+func (s *WordService) UpdateHeadword(userID, wordID uint, headword models.Headword) error {
+	return updateWord(userID, wordID, map[string]interface{}{
+		"english_word":   headword.EnglishWord,
+		"transcription":  headword.Transcription,
+		"part_of_speech": headword.PartOfSpeech,
+	})
+}
+
+// UpdateTranslation replaces the translations of the user's word.
+func (s *WordService) UpdateTranslation(userID, wordID uint, translation string) error {
+	return updateWord(userID, wordID, map[string]interface{}{
+		"translation": models.JoinTranslations(models.SplitTranslations(translation)),
+	})
+}
+
+// UpdateExamples replaces the example sentences of the user's word.
+func (s *WordService) UpdateExamples(userID, wordID uint, examples []string) error {
+	return updateWord(userID, wordID, map[string]interface{}{"examples": strings.Join(examples, "\n")})
+}
+
+// UpdateNote replaces the note of the user's word.
+func (s *WordService) UpdateNote(userID, wordID uint, note string) error {
+	return updateWord(userID, wordID, map[string]interface{}{"note": strings.TrimSpace(note)})
+}
+
+// updateWord sets columns of the user's word, returning ErrWordNotFound if
+// the user has no such word.
+func updateWord(userID, wordID uint, values map[string]interface{}) error {
+	result := db.DB.Model(&models.Word{}).Where("id = ? AND user_id = ?", wordID, userID).Updates(values)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrWordNotFound
+	}
+	return result.Error
 }
 
-func (s *WordService) DeleteWord(wordID uint) error {
-	return db.DB.Delete(&models.Word{}, wordID).Error
+// DeleteWord moves the user's word to the trash.
+func (s *WordService) DeleteWord(userID, wordID uint) error {
+	result := db.DB.Where("id = ? AND user_id = ?", wordID, userID).Delete(&models.Word{})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrWordNotFound
+	}
+	return result.Error
+}
+
+// GetDeletedWordsPage returns one page of the user's words in the trash,
+// most recently deleted first, together with the total number of them.
+func (s *WordService) GetDeletedWordsPage(userID uint, page, pageSize int) ([]models.Word, int64, error) {
+	deleted := db.DB.Unscoped().Model(&models.Word{}).
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+
+	var total int64
+	if err := deleted.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var words []models.Word
+	err := deleted.Session(&gorm.Session{}).Order("deleted_at DESC, id").
+		Offset(page * pageSize).Limit(pageSize).
+		Find(&words).Error
+	return words, total, err
 }
 
+// RestoreWord brings a word of the user back from the trash, or returns
+// ErrWordNotFound if the user has no such word there. If the dictionary
+// already has the same word, matched as in AddWord, the word stays in the
+// trash and ErrWordExists is returned with the existing word.
+func (s *WordService) RestoreWord(userID, wordID uint) (*models.Word, error) {
+	var words []models.Word
+	err := db.DB.Unscoped().
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", wordID, userID).
+		Limit(1).Find(&words).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, ErrWordNotFound
+	}
+
+	word := &words[0]
+	existing, err := s.FindWord(userID, word.Headword())
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, ErrWordExists
+	}
+
+	word.DeletedAt = gorm.DeletedAt{}
+	return word, db.DB.Unscoped().Model(word).Update("deleted_at", nil).Error
+}
+
+// PurgeWord deletes a word of the user from the trash for good.
+func (s *WordService) PurgeWord(userID, wordID uint) error {
+	trashed := db.DB.Unscoped().Model(&models.Word{}).Select("id").
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", wordID, userID)
+
+	purged, err := purgeWords(trashed)
+	if err == nil && purged == 0 {
+		err = ErrWordNotFound
+	}
+	return err
+}
+
+// PurgeDeletedWords deletes for good all words that were put in the trash
+// before the given time, returning how many were deleted.
+func (s *WordService) PurgeDeletedWords(before time.Time) (int64, error) {
+	return purgeWords(db.DB.Unscoped().Model(&models.Word{}).Select("id").
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before))
+}
+
+// purgeWords hard-deletes the words whose IDs the query selects, together
+// with their deck links.
+func purgeWords(ids *gorm.DB) (int64, error) {
+	var purged int64
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM word_decks WHERE word_id IN (?)", ids).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("id IN (?)", ids).Delete(&models.Word{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
+
+// GetRandomWord returns a random word out of the ones the user trains on, or
+// ErrWordNotFound if there are none.
 func (s *WordService) GetRandomWord(userID uint) (*models.Word, error) {
-	var word models.Word
-	err := db.DB.Where("user_id = ?", userID).Order("RANDOM()").First(&word).Error
-	return &word, err
+	var words []models.Word
+	err := s.trainingWords(userID).Order("RANDOM()").Limit(1).Find(&words).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, ErrWordNotFound
+	}
+	return &words[0], nil
+}
+
+// GetQuizCandidates returns up to limit of the user's words other than the
+// given one whose answer side, the English word if reversed and the
+// translation otherwise, is closest in length to length. Words at the same
+// distance come in random order.
+func (s *WordService) GetQuizCandidates(userID, wordID uint, reversed bool, length, limit int) ([]models.Word, error) {
+	column := "translation"
+	if reversed {
+		column = "english_word"
+	}
+
+	var words []models.Word
+	err := db.DB.Where("user_id = ? AND id <> ?", userID, wordID).
+		Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "ABS(LENGTH(" + column + ") - ?), RANDOM()",
+			Vars: []interface{}{length},
+		}}).
+		Limit(limit).Find(&words).Error
+	return words, err
+}
+
+// GetTrainingWords returns the words the user trains on: the words in the
+// decks selected for training, or the whole dictionary if none is selected.
+func (s *WordService) GetTrainingWords(userID uint) ([]models.Word, error) {
+	var words []models.Word
+	err := s.trainingWords(userID).Order("id").Find(&words).Error
+	return words, err
+}
+
+// trainingWords starts a query over the words the user trains on, limited to
+// the selected decks and part of speech if any.
+func (s *WordService) trainingWords(userID uint) *gorm.DB {
+	selected := db.DB.Model(&models.Deck{}).Select("id").Where("user_id = ? AND train = ?", userID, true)
+	inSelected := db.DB.Table("word_decks").Select("word_id").Where("deck_id IN (?)", selected)
+	partOfSpeech := db.DB.Model(&models.User{}).Select("training_part_of_speech").Where("id = ?", userID)
+	return db.DB.Where("user_id = ?", userID).
+		Where("(NOT EXISTS (?) OR id IN (?))", selected, inSelected).
+		Where("COALESCE((?), '') IN ('', part_of_speech)", partOfSpeech)
 }
 
-func (s *WordService) GetWordByID(wordID uint) (*models.Word, error) {
-	var word models.Word
-	err := db.DB.Where("id = ?", wordID).First(&word).Error
-	return &word, err
+// GetWordByID returns the user's word with the given ID, or ErrWordNotFound
+// if the user has no such word.
+func (s *WordService) GetWordByID(userID, wordID uint) (*models.Word, error) {
+	var words []models.Word
+	err := db.DB.Where("id = ? AND user_id = ?", wordID, userID).Limit(1).Find(&words).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, ErrWordNotFound
+	}
+	return &words[0], nil
+}
+
+// SearchWords returns one page of the user's words whose English word or
+// translation contains the query, prefix matches first, together with the
+// total number of matches.
+func (s *WordService) SearchWords(userID uint, query string, page, pageSize int) ([]models.Word, int64, error) {
+	query = strings.TrimSpace(query)
+	// SQLite compares only ASCII letters case-insensitively, so the query is
+	// also tried lower-cased and capitalized for other alphabets.
+	variants := []string{query, strings.ToLower(query)}
+	if r, size := utf8.DecodeRuneInString(variants[1]); size > 0 {
+		variants = append(variants, string(unicode.ToUpper(r))+variants[1][size:])
+	}
+
+	var conditions []string
+	var contains, prefix []interface{}
+	for _, v := range variants {
+		conditions = append(conditions, `english_word LIKE ? ESCAPE '\' OR translation LIKE ? ESCAPE '\'`)
+		v = escapeLike(v)
+		contains = append(contains, "%"+v+"%", "%"+v+"%")
+		prefix = append(prefix, v+"%", v+"%")
+	}
+	match := "(" + strings.Join(conditions, " OR ") + ")"
+
+	scope := db.DB.Model(&models.Word{}).Where("user_id = ?", userID).Where(match, contains...)
+
+	var total int64
+	if err := scope.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var words []models.Word
+	err := scope.Session(&gorm.Session{}).
+		Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "CASE WHEN " + match + " THEN 0 ELSE 1 END, id",
+			Vars: prefix,
+		}}).
+		Offset(page * pageSize).Limit(pageSize).
+		Find(&words).Error
+	return words, total, err
+}
+
+// escapeLike escapes the LIKE wildcards in s.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// GetDueWords returns the training words whose review is due at the given
+// time, most overdue first. Words that were never reviewed are due from the
+// moment they were added.
+func (s *WordService) GetDueWords(userID uint, now time.Time) ([]models.Word, error) {
+	var words []models.Word
+	err := s.trainingWords(userID).Where("due_at IS NULL OR due_at <= ?", now).
+		Order("COALESCE(due_at, created_at)").
+		Find(&words).Error
+	return words, err
+}
+
+// RecordReview updates the spaced repetition state of the user's word after
+// an answer of the given quality.
+func (s *WordService) RecordReview(userID, wordID uint, quality int) error {
+	word, err := s.GetWordByID(userID, wordID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	state := srs.Review(srs.State{
+		EaseFactor:  word.EaseFactor,
+		Interval:    word.Interval,
+		Repetitions: word.Repetitions,
+	}, quality, now)
+
+	return db.DB.Model(word).Updates(map[string]interface{}{
+		"ease_factor":      state.EaseFactor,
+		"interval":         state.Interval,
+		"repetitions":      state.Repetitions,
+		"due_at":           state.DueAt,
+		"last_reviewed_at": now,
+	}).Error
+}
+
+// GetLeitnerDueWords returns the training words whose Leitner box is due for
+// review, lower boxes first.
+func (s *WordService) GetLeitnerDueWords(userID uint, now time.Time) ([]models.Word, error) {
+	var words []models.Word
+	err := s.trainingWords(userID).Where("leitner_due_at IS NULL OR leitner_due_at <= ?", now).
+		Order("leitner_box, COALESCE(leitner_due_at, created_at)").
+		Find(&words).Error
+	return words, err
+}
+
+// RecordLeitnerReview moves the user's word between Leitner boxes after an
+// answer and returns the box it ended up in.
+func (s *WordService) RecordLeitnerReview(userID, wordID uint, correct bool) (int, error) {
+	word, err := s.GetWordByID(userID, wordID)
+	if err != nil {
+		return 0, err
+	}
+
+	box, dueAt := srs.LeitnerReview(word.LeitnerBox, correct, time.Now())
+	err = db.DB.Model(word).Updates(map[string]interface{}{
+		"leitner_box":    box,
+		"leitner_due_at": dueAt,
+	}).Error
+	return box, err
 }
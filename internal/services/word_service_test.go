@@ -0,0 +1,186 @@
+package services
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"english-words-bot/internal/db"
+	"english-words-bot/internal/models"
+	"english-words-bot/internal/srs"
+)
+
+// openTestDB points db.DB at a fresh SQLite database in a temporary directory.
+func openTestDB(t *testing.T) {
+	t.Helper()
+	if err := db.Open(filepath.Join(t.TempDir(), "words.db")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if conn, err := db.DB.DB(); err == nil {
+			conn.Close()
+		}
+	})
+}
+
+// loadWord reads a word row as it is stored, including a trashed one.
+func loadWord(t *testing.T, wordID uint) models.Word {
+	t.Helper()
+	var word models.Word
+	if err := db.DB.Unscoped().First(&word, wordID).Error; err != nil {
+		t.Fatalf("loading word %d: %v", wordID, err)
+	}
+	return word
+}
+
+func TestWordServiceOtherUser(t *testing.T) {
+	openTestDB(t)
+
+	users := &UserService{}
+	owner, err := users.GetOrCreateUser(1001, "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := users.GetOrCreateUser(1002, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &WordService{}
+	_, word, err := s.AddWord(owner.ID, models.Headword{EnglishWord: "bank", PartOfSpeech: "n."}, "берег")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateNote(owner.ID, word.ID, "river bank"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateExamples(owner.ID, word.ID, []string{"We sat on the bank."}); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := []struct {
+		name string
+		call func() error
+	}{
+		{"GetWordByID", func() error {
+			_, err := s.GetWordByID(other.ID, word.ID)
+			return err
+		}},
+		{"UpdateWord", func() error {
+			return s.UpdateWord(other.ID, word.ID, models.Headword{EnglishWord: "stolen"}, "вкрадено")
+		}},
+		{"UpdateHeadword", func() error {
+			return s.UpdateHeadword(other.ID, word.ID, models.Headword{EnglishWord: "stolen"})
+		}},
+		{"UpdateTranslation", func() error {
+			return s.UpdateTranslation(other.ID, word.ID, "вкрадено")
+		}},
+		{"UpdateNote", func() error {
+			return s.UpdateNote(other.ID, word.ID, "stolen")
+		}},
+		{"UpdateExamples", func() error {
+			return s.UpdateExamples(other.ID, word.ID, []string{"stolen"})
+		}},
+		{"MergeTranslations", func() error {
+			_, err := s.MergeTranslations(other.ID, word.ID, []string{"банк"})
+			return err
+		}},
+		{"RecordReview", func() error {
+			return s.RecordReview(other.ID, word.ID, srs.QualityPerfect)
+		}},
+		{"RecordLeitnerReview", func() error {
+			_, err := s.RecordLeitnerReview(other.ID, word.ID, true)
+			return err
+		}},
+		{"DeleteWord", func() error {
+			return s.DeleteWord(other.ID, word.ID)
+		}},
+	}
+
+	before := loadWord(t, word.ID)
+	for _, c := range calls {
+		if err := c.call(); !errors.Is(err, ErrWordNotFound) {
+			t.Errorf("%s by another user: error = %v, want ErrWordNotFound", c.name, err)
+		}
+		if after := loadWord(t, word.ID); !reflect.DeepEqual(after, before) {
+			t.Errorf("%s by another user changed the word:\n got %+v\nwant %+v", c.name, after, before)
+		}
+	}
+
+	// Restoring and purging work on the trash, so the owner deletes the word
+	if err := s.DeleteWord(owner.ID, word.ID); err != nil {
+		t.Fatal(err)
+	}
+	trashed := loadWord(t, word.ID)
+	trashCalls := []struct {
+		name string
+		call func() error
+	}{
+		{"RestoreWord", func() error {
+			_, err := s.RestoreWord(other.ID, word.ID)
+			return err
+		}},
+		{"PurgeWord", func() error {
+			return s.PurgeWord(other.ID, word.ID)
+		}},
+	}
+	for _, c := range trashCalls {
+		if err := c.call(); !errors.Is(err, ErrWordNotFound) {
+			t.Errorf("%s by another user: error = %v, want ErrWordNotFound", c.name, err)
+		}
+		if after := loadWord(t, word.ID); !reflect.DeepEqual(after, trashed) {
+			t.Errorf("%s by another user changed the word:\n got %+v\nwant %+v", c.name, after, trashed)
+		}
+	}
+
+	// The owner still has full access
+	if _, err := s.RestoreWord(owner.ID, word.ID); err != nil {
+		t.Errorf("RestoreWord by the owner: %v", err)
+	}
+	if _, err := s.GetWordByID(owner.ID, word.ID); err != nil {
+		t.Errorf("GetWordByID by the owner: %v", err)
+	}
+}
+
+func TestRestoreWordAddedAgain(t *testing.T) {
+	openTestDB(t)
+
+	users := &UserService{}
+	user, err := users.GetOrCreateUser(1001, "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &WordService{}
+	_, old, err := s.AddWord(user.ID, models.Headword{EnglishWord: "cat"}, "кіт")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteWord(user.ID, old.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, again, err := s.AddWord(user.ID, models.Headword{EnglishWord: "Cat"}, "кішка")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing, err := s.RestoreWord(user.ID, old.ID)
+	if !errors.Is(err, ErrWordExists) {
+		t.Fatalf("RestoreWord error = %v, want ErrWordExists", err)
+	}
+	if existing == nil || existing.ID != again.ID {
+		t.Errorf("RestoreWord returned %+v, want the word added again (ID %d)", existing, again.ID)
+	}
+	if !loadWord(t, old.ID).DeletedAt.Valid {
+		t.Error("the old word was taken out of the trash")
+	}
+
+	// Once the new word is gone, the old one can come back
+	if err := s.DeleteWord(user.ID, again.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RestoreWord(user.ID, old.ID); err != nil {
+		t.Errorf("RestoreWord after deleting the new word: %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package srs
+
+import "time"
+
+// LeitnerBoxes is the number of boxes in the Leitner system.
+const LeitnerBoxes = 5
+
+// leitnerCadence is how many days pass before a word in each box is reviewed
+// again: box 1 daily, box 2 every 2 days, box 3 weekly and so on.
+var leitnerCadence = [LeitnerBoxes]int{1, 2, 7, 14, 30}
+
+// LeitnerReview moves a word one box up after a correct answer or back to the
+// first box after a miss, and returns the new box with its next due date.
+func LeitnerReview(box int, correct bool, now time.Time) (int, time.Time) {
+	if box < 1 {
+		box = 1
+	}
+
+	if correct {
+		if box < LeitnerBoxes {
+			box++
+		}
+	} else {
+		box = 1
+	}
+
+	return box, now.AddDate(0, 0, leitnerCadence[box-1])
+}
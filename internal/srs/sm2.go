@@ -0,0 +1,62 @@
+package srs
+
+import (
+	"math"
+	"time"
+)
+
+// Answer quality grades on the SM-2 0-5 scale.
+const (
+	QualityWrong   = 1
+	QualityHard    = 3
+	QualityPerfect = 5
+)
+
+// DefaultEaseFactor is the ease factor every new word starts with.
+const DefaultEaseFactor = 2.5
+
+// MasteredInterval is the review interval in days from which a word counts
+// as mastered.
+const MasteredInterval = 21
+
+const minEaseFactor = 1.3
+
+// State is the per-word spaced repetition state tracked by SM-2.
+type State struct {
+	EaseFactor  float64
+	Interval    int // days until the next review
+	Repetitions int
+	DueAt       time.Time
+}
+
+// Review applies one answer of the given quality to the state and returns
+// the updated state with the next due date counted from now.
+func Review(s State, quality int, now time.Time) State {
+	if s.EaseFactor < minEaseFactor {
+		s.EaseFactor = DefaultEaseFactor
+	}
+
+	if quality >= QualityHard {
+		switch s.Repetitions {
+		case 0:
+			s.Interval = 1
+		case 1:
+			s.Interval = 6
+		default:
+			s.Interval = int(math.Round(float64(s.Interval) * s.EaseFactor))
+		}
+		s.Repetitions++
+	} else {
+		s.Repetitions = 0
+		s.Interval = 1
+	}
+
+	q := float64(5 - quality)
+	s.EaseFactor += 0.1 - q*(0.08+q*0.02)
+	if s.EaseFactor < minEaseFactor {
+		s.EaseFactor = minEaseFactor
+	}
+
+	s.DueAt = now.AddDate(0, 0, s.Interval)
+	return s
+}